@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+const (
+	recentlyAddedSystem = "Recently Added"
+	defaultNewDays      = 7
+	newBadge            = "[green]NEW[-]"
+)
+
+// newDays is how recently a ROM must have changed to count as new
+var newDays = defaultNewDays
+
+// isNew reports whether a scanned game's file changed within newDays
+func isNew(game GameEntry) bool {
+	return !game.ModTime.IsZero() && time.Since(game.ModTime) < time.Duration(newDays)*24*time.Hour
+}
+
+// keepsOrder reports whether a list has its own order that sorting would lose
+func keepsOrder(system string) bool {
+	return system == recentSystem || system == mostPlayedSystem || system == recentlyAddedSystem || isPlaylist(system)
+}
+
+// recentlyAddedContext collects the new games of every system, newest first.
+// It reuses the scan cache, whose modification times come from the scan.
+func recentlyAddedContext(ctx context.Context, progress func(int)) ([]GameEntry, error) {
+	var added []GameEntry
+	total := 0
+	for _, sys := range listedSystems() {
+		base := total
+		games, err := systemGamesContext(ctx, sys.Name, func(n int) {
+			if progress != nil {
+				progress(base + n)
+			}
+		})
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			logWarn("%s: %v", sys.Name, err)
+		}
+		total += len(games)
+		for _, g := range games {
+			if isNew(g) {
+				added = append(added, g)
+			}
+		}
+	}
+	sort.SliceStable(added, func(i, j int) bool {
+		return added[i].ModTime.After(added[j].ModTime)
+	})
+	return added, nil
+}
+
+// listedSystems are the configured systems shown in the system list
+func listedSystems() []SystemConfig {
+	var listed []SystemConfig
+	for _, sys := range provider.Systems() {
+		if showAll || coreAvailable(sys.Name) {
+			listed = append(listed, sys)
+		}
+	}
+	return listed
+}
+
+// allCached reports whether every listed system has been scanned
+func allCached() bool {
+	for _, sys := range listedSystems() {
+		if !isCached(sys.Name) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// arcadeSystem lists the MRA files of the arcade cores, unless the systems
+// file configures a system of that name itself
+const arcadeSystem = "Arcade"
+
+// arcadeDir is where the MRA files live
+var arcadeDir = filepath.Join(misterRoot, "_Arcade")
+
+// ArcadeEntry is what an MRA file says about a game
+type ArcadeEntry struct {
+	Path    string
+	Name    string
+	SetName string
+	Rbf     string
+	// Zips lists, per ROM of the game, the archives any of which holds it
+	Zips [][]string
+}
+
+// mraFile is the part of an MRA document parseMRA reads
+type mraFile struct {
+	Name    string `xml:"name"`
+	SetName string `xml:"setname"`
+	Rbf     string `xml:"rbf"`
+	Roms    []struct {
+		Zip string `xml:"zip,attr"`
+	} `xml:"rom"`
+}
+
+// parseMRA reads an MRA file. Its display name comes from <name>, falling
+// back to the file name.
+func parseMRA(path string) (ArcadeEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ArcadeEntry{}, err
+	}
+	var m mraFile
+	if err := xml.Unmarshal(data, &m); err != nil {
+		return ArcadeEntry{}, fmt.Errorf("%s: %w", path, err)
+	}
+	e := ArcadeEntry{
+		Path:    path,
+		Name:    strings.TrimSpace(m.Name),
+		SetName: strings.TrimSpace(m.SetName),
+		Rbf:     strings.TrimSpace(m.Rbf),
+	}
+	if e.Name == "" {
+		e.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for _, r := range m.Roms {
+		if r.Zip == "" {
+			continue
+		}
+		var zips []string
+		for _, z := range strings.Split(r.Zip, "|") {
+			if z = strings.TrimSpace(z); z != "" {
+				zips = append(zips, z)
+			}
+		}
+		e.Zips = append(e.Zips, zips)
+	}
+	return e, nil
+}
+
+// arcadeRomDirs are the folders MiSTer looks in for arcade ROM zips
+func arcadeRomDirs() []string {
+	var dirs []string
+	for _, base := range []string{gamesDir, arcadeDir} {
+		dirs = append(dirs, filepath.Join(base, "mame"), filepath.Join(base, "hbmame"))
+	}
+	return dirs
+}
+
+// romZips is the set of files in the arcade ROM folders, read once per
+// listing so checking every MRA doesn't stat each archive in each folder
+type romZips map[string]bool
+
+// readRomZips lists the files directly in the arcade ROM folders
+func readRomZips() romZips {
+	have := romZips{}
+	for _, dir := range arcadeRomDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			have[e.Name()] = true
+		}
+	}
+	return have
+}
+
+// has reports whether archive z is in one of the folders. Names with a
+// folder of their own aren't in the set, so they are looked up directly.
+func (have romZips) has(z string) bool {
+	if !strings.ContainsAny(z, `/\`) {
+		return have[z]
+	}
+	for _, dir := range arcadeRomDirs() {
+		if _, err := os.Stat(filepath.Join(dir, z)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// missingZips lists the ROMs of e none of whose archives are in have,
+// naming the archives they were looked for in
+func (e ArcadeEntry) missingZips(have romZips) []string {
+	var missing []string
+	for _, zips := range e.Zips {
+		found := false
+		for _, z := range zips {
+			if have.has(z) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, strings.Join(zips, " or "))
+		}
+	}
+	return missing
+}
+
+// arcadeParsed remembers each MRA by path and modification time, so opening
+// the system again doesn't parse them all over
+type arcadeParsed struct {
+	mod     time.Time
+	entry   ArcadeEntry
+	missing []string
+}
+
+var (
+	arcadeMu    sync.Mutex
+	arcadeCache = map[string]arcadeParsed{}
+)
+
+// isArcade reports whether system is the built-in arcade system
+func isArcade(system string) bool {
+	if system != arcadeSystem {
+		return false
+	}
+	_, configured := cfg.findSystem(arcadeSystem)
+	return !configured
+}
+
+// hasArcade reports whether there is an arcade folder to list
+func hasArcade() bool {
+	info, err := os.Stat(arcadeDir)
+	return err == nil && info.IsDir() && isArcade(arcadeSystem)
+}
+
+// arcadeGames lists the MRA files as games, by their display names. Each
+// launches itself with the core it names; ROM sets are checked again on
+// every listing, since they are often added after the MRAs.
+func arcadeGames() ([]GameEntry, error) {
+	matches, err := filepath.Glob(filepath.Join(arcadeDir, "*.mra"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	games := make([]GameEntry, 0, len(matches))
+	have := readRomZips()
+	arcadeMu.Lock()
+	defer arcadeMu.Unlock()
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+		p, ok := arcadeCache[m]
+		if !ok || !p.mod.Equal(info.ModTime()) {
+			e, err := parseMRA(m)
+			if err != nil {
+				logWarn("%v", err)
+				continue
+			}
+			p = arcadeParsed{mod: info.ModTime(), entry: e}
+		}
+		p.missing = p.entry.missingZips(have)
+		arcadeCache[m] = p
+		games = append(games, GameEntry{
+			Title:   p.entry.Name,
+			Path:    m,
+			System:  arcadeSystem,
+			Command: "load_core " + m,
+		})
+	}
+	return games, nil
+}
+
+// countArcade parses the MRAs in the background and shows how many there are
+func countArcade() {
+	games, err := provider.Games(arcadeSystem)
+	if err != nil {
+		logWarn("%s: %v", arcadeDir, err)
+		return
+	}
+	n := len(games)
+	ui.update(func() {
+		setSystemCount(arcadeSystem, n)
+	})
+}
+
+// arcadeMissing lists the ROM sets an arcade game lacks; nil for runnable
+// games and anything that isn't an MRA
+func arcadeMissing(game GameEntry) []string {
+	if !isArcade(game.System) {
+		return nil
+	}
+	arcadeMu.Lock()
+	defer arcadeMu.Unlock()
+	return arcadeCache[game.Path].missing
+}
+
+// arcadeDetails describes an MRA's core and ROM sets for the details pane
+func arcadeDetails(game GameEntry) string {
+	if !isArcade(game.System) {
+		return ""
+	}
+	arcadeMu.Lock()
+	p, ok := arcadeCache[game.Path]
+	arcadeMu.Unlock()
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	if p.entry.Rbf != "" {
+		fmt.Fprintf(&b, "Core:     %s\n", p.entry.Rbf)
+	}
+	if p.entry.SetName != "" {
+		fmt.Fprintf(&b, "Set:      %s\n", p.entry.SetName)
+	}
+	if len(p.missing) > 0 {
+		fmt.Fprintf(&b, "[red]Missing ROMs: %s[-]\n", strings.Join(p.missing, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestArcadeMissingZips checks each ROM is found in any of the ROM folders,
+// by any of its alternative archives
+func TestArcadeMissingZips(t *testing.T) {
+	oldCfg, oldGames, oldArcade := cfg, gamesDir, arcadeDir
+	t.Cleanup(func() { cfg, gamesDir, arcadeDir = oldCfg, oldGames, oldArcade })
+	cfg = defaultConfig()
+	gamesDir = t.TempDir()
+	arcadeDir = filepath.Join(t.TempDir(), "_Arcade")
+
+	for _, f := range []string{
+		filepath.Join(gamesDir, "mame", "pacman.zip"),
+		filepath.Join(arcadeDir, "hbmame", "puckman.zip"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mra := `<misterromdescription><name>Pac-Man</name>
+		<rom zip="pacman.zip"/><rom zip="absent.zip|puckman.zip"/><rom zip="gone.zip|lost.zip"/>
+	</misterromdescription>`
+	path := filepath.Join(arcadeDir, "Pac-Man.mra")
+	if err := os.WriteFile(path, []byte(mra), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	games, err := arcadeGames()
+	if err != nil || len(games) != 1 {
+		t.Fatalf("arcadeGames = %v, %v", games, err)
+	}
+	if got, want := arcadeMissing(games[0]), []string{"gone.zip or lost.zip"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("missing = %q, want %q", got, want)
+	}
+}
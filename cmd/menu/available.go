@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	// availableMu guards available, which warming reads off the UI goroutine
+	availableMu sync.Mutex
+	// available records, per configured system, whether it could be played
+	// when last checked
+	available = map[string]bool{}
+)
+
+// unavailableReason says why a configured system can't be played, or ""
+// if it can: its core is not installed, or the provider has nowhere to list
+// its games from
+func unavailableReason(sys SystemConfig) string {
+	if !coreAvailable(sys.Name) {
+		return "core " + sys.coreName() + " is not installed"
+	}
+	return provider.Unavailable(sys)
+}
+
+// resolveAvailability checks a configured system again and records the
+// result, which it returns
+func resolveAvailability(system string) bool {
+	sys, ok := cfg.findSystem(system)
+	if !ok {
+		return true
+	}
+	ok = unavailableReason(sys) == ""
+	availableMu.Lock()
+	available[system] = ok
+	availableMu.Unlock()
+	return ok
+}
+
+// systemAvailable reports whether system could be played when last
+// checked; virtual and unchecked systems count as available
+func systemAvailable(system string) bool {
+	availableMu.Lock()
+	defer availableMu.Unlock()
+	ok, known := available[system]
+	return ok || !known
+}
+
+// openSystem loads all of system's games, or explains why it is unavailable
+func openSystem(system string) {
+	currentBucket = nil
+	if !systemAvailable(system) {
+		sys, _ := cfg.findSystem(system)
+		if reason := unavailableReason(sys); reason != "" {
+			showMessage(app, fmt.Sprintf(T("msg.unavailable"), system, reason))
+			return
+		}
+		// It has come back since the list was built
+		resolveAvailability(system)
+		refreshSystemLabel(system)
+	}
+	loadGamesThen(system, func() {
+		if autoHideSystems {
+			hideSystems()
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package main
+
+// BootOption is a way of starting a system's core, such as cart or disk,
+// or a BIOS region. Its args are added to the launch command.
+type BootOption struct {
+	Name string   `yaml:"name"`
+	Args []string `yaml:"args,omitempty"`
+}
+
+// bootOptionsFor returns the boot options a system declares, if any
+func bootOptionsFor(system string) []BootOption {
+	sys, ok := cfg.findSystem(system)
+	if !ok {
+		return nil
+	}
+	return sys.Boot
+}
+
+// withBoot is game launched with opt: its args follow the game's own
+func withBoot(game GameEntry, opt BootOption) GameEntry {
+	game.Args = append(append([]string(nil), game.Args...), opt.Args...)
+	return game
+}
+
+// showBootOptions asks how to boot game and then launches it that way
+func showBootOptions(game GameEntry, opts []BootOption) {
+	labels := make([]string, len(opts))
+	for i, opt := range opts {
+		labels[i] = opt.Name
+	}
+	showPicker("Boot "+displayTitle(game), labels, 0, func(i int) {
+		launchNow(withBoot(game, opts[i]))
+	})
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// breadcrumbSep goes between the parts of the breadcrumb
+const breadcrumbSep = " / "
+
+// breadcrumb shows the system and folder of the highlighted row, like
+// "NES / Licensed / USA"; clicking a part jumps to that folder's header
+var breadcrumb *tview.TextView
+
+// breadcrumbParts are the folders behind the breadcrumb's regions; region
+// "0" is the system itself
+var breadcrumbParts []string
+
+// newBreadcrumb creates the header above the search box
+func newBreadcrumb() *tview.TextView {
+	tv := tview.NewTextView().SetDynamicColors(true).SetRegions(true).SetWrap(false)
+	tv.SetHighlightedFunc(func(added, _, _ []string) {
+		if len(added) == 0 {
+			return
+		}
+		// Clicks only jump; don't leave the part highlighted
+		tv.Highlight()
+		if n, err := strconv.Atoi(added[0]); err == nil {
+			jumpToFolder(n)
+		}
+	})
+	return tv
+}
+
+// refreshBreadcrumb shows where the highlighted row lives, dropping parts
+// from the left when the path is wider than the header
+func refreshBreadcrumb() {
+	if breadcrumb == nil {
+		return
+	}
+	if currentSystem == "" {
+		breadcrumb.SetText("")
+		return
+	}
+	folder := ""
+	if i := gameList.GetCurrentItem(); i >= 0 && i < len(ui.shownRows) {
+		if node := ui.shownRows[i].folder; node != nil {
+			folder = node.Path
+		} else {
+			folder = ui.shownRows[i].game.Folder
+		}
+	}
+
+	names := []string{currentSystem}
+	breadcrumbParts = []string{""}
+	if folder != "" {
+		parts := strings.Split(folder, "/")
+		for k, name := range parts {
+			names = append(names, name)
+			breadcrumbParts = append(breadcrumbParts, strings.Join(parts[:k+1], "/"))
+		}
+	}
+
+	_, _, width, _ := breadcrumb.GetInnerRect()
+	first := 0
+	for width > 0 && first < len(names)-1 && crumbWidth(names[first:], first > 0) > width {
+		first++
+	}
+	var b strings.Builder
+	if first > 0 {
+		b.WriteString("…" + breadcrumbSep)
+	}
+	for k := first; k < len(names); k++ {
+		if k > first {
+			b.WriteString(breadcrumbSep)
+		}
+		fmt.Fprintf(&b, `["%d"][::b]%s[::-][""]`, k, tview.Escape(names[k]))
+	}
+	breadcrumb.SetText(b.String())
+}
+
+// crumbWidth is how wide names are shown joined, with a leading "… / "
+// when truncated
+func crumbWidth(names []string, truncated bool) int {
+	width := len([]rune(strings.Join(names, breadcrumbSep)))
+	if truncated {
+		width += 1 + len(breadcrumbSep)
+	}
+	return width
+}
+
+// jumpToFolder moves the cursor to the header of breadcrumb part n, or to
+// the top of the list for the system
+func jumpToFolder(n int) {
+	if n <= 0 || n >= len(breadcrumbParts) {
+		pagedGames.Select(0)
+		return
+	}
+	for i, r := range ui.shownRows {
+		if r.folder != nil && r.folder.Path == breadcrumbParts[n] {
+			pagedGames.Select(i)
+			return
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+// letterBuckets are the title ranges a big system is split into, by index
+// letter; '#' sorts before the letters
+var letterBuckets = []struct{ from, to byte }{
+	{'#', '#'}, {'A', 'F'}, {'G', 'M'}, {'N', 'S'}, {'T', 'Z'},
+}
+
+// bucketMin is how many games a system needs before it is listed as letter
+// buckets; 0 never splits
+var bucketMin int
+
+// systemBucket is one letter range of a split system
+type systemBucket struct {
+	system   string
+	from, to byte
+	count    int
+}
+
+// label names the bucket's range, e.g. "A–F"
+func (b systemBucket) label() string {
+	if b.from == b.to {
+		return string(b.from)
+	}
+	return string(b.from) + "–" + string(b.to)
+}
+
+// name is the bucket's row in the system list, e.g. "NES: A–F"
+func (b systemBucket) name() string {
+	return b.system + ": " + b.label()
+}
+
+// holdsTitle reports whether a title files under the bucket
+func (b systemBucket) holdsTitle(title string) bool {
+	letter := titleLetter(title)
+	return letter >= b.from && letter <= b.to
+}
+
+var (
+	// bucketRows maps bucket row names back to their buckets, and
+	// bucketed marks the systems listed as buckets
+	bucketRows = map[string]systemBucket{}
+	bucketed   = map[string]bool{}
+
+	// currentBucket limits the game list to one bucket of currentSystem
+	currentBucket *systemBucket
+
+	// splitSystems holds the buckets worked out for each split system since
+	// its count last changed, so building the list doesn't reread its games
+	splitSystems = map[string][]systemBucket{}
+)
+
+// systemBuckets splits a counted system with more than bucketMin games into
+// its non-empty buckets, or returns nil when it stays whole. Systems stay
+// whole until their scan has been counted and cached.
+func systemBuckets(system string) []systemBucket {
+	if _, ok := cfg.findSystem(system); !ok || bucketMin <= 0 {
+		return nil
+	}
+	if n, counted := ui.systemCounts[system]; !counted || n <= bucketMin {
+		return nil
+	}
+	if buckets, ok := splitSystems[system]; ok {
+		return buckets
+	}
+	if !provider.Cached(system) {
+		return nil
+	}
+	games, _ := provider.Games(system)
+	var buckets []systemBucket
+	for _, r := range letterBuckets {
+		b := systemBucket{system: system, from: r.from, to: r.to}
+		for _, g := range games {
+			if b.holdsTitle(displayTitle(g)) {
+				b.count++
+			}
+		}
+		if b.count > 0 {
+			buckets = append(buckets, b)
+		}
+	}
+	splitSystems[system] = buckets
+	return buckets
+}
+
+// addSystemRows lists a configured system as one row, or as a row per
+// bucket when it is big enough to split
+func addSystemRows(sys SystemConfig) {
+	buckets := systemBuckets(sys.Name)
+	if buckets == nil {
+		delete(bucketed, sys.Name)
+		addSystemItem(sys.Name, sys.Description, sys.hotkeyRune())
+		return
+	}
+	bucketed[sys.Name] = true
+	for i, b := range buckets {
+		b := b
+		hotkey := rune(0)
+		if i == 0 {
+			hotkey = sys.hotkeyRune()
+		}
+		bucketRows[b.name()] = b
+		systemOrder = append(systemOrder, b.name())
+		text := fmt.Sprintf("(%d games)", b.count)
+		if b.count == 1 {
+			text = "(1 game)"
+		}
+		if sys.Description != "" {
+			text = sys.Description + " " + text
+		}
+		systemList.AddItem(systemLabel(sys.Name)+": "+tview.Escape(b.label()), text, hotkey, func() {
+			openBucket(b)
+		})
+	}
+}
+
+// openBucket lists the games of one bucket
+func openBucket(b systemBucket) {
+	if !systemAvailable(b.system) {
+		openSystem(b.system)
+		return
+	}
+	currentBucket = &b
+	if currentSystem == b.system {
+		letterFilter = 0
+		filterGames(searchBox.GetText())
+		if len(ui.shownRows) > 0 {
+			pagedGames.Select(0)
+		}
+		return
+	}
+	loadGames(app, b.system)
+}
+
+// leaveBucket drops the bucket filter when another system is shown
+func leaveBucket(system string) {
+	if currentBucket != nil && currentBucket.system != system {
+		currentBucket = nil
+	}
+}
+
+// filteredByBucket returns the games in the open bucket, in their order
+func filteredByBucket(games []GameEntry) []GameEntry {
+	if currentBucket == nil || currentBucket.system != currentSystem {
+		return games
+	}
+	var out []GameEntry
+	for _, g := range games {
+		if currentBucket.holdsTitle(displayTitle(g)) {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// realSystem is the system behind a system list row name
+func realSystem(name string) string {
+	if b, ok := bucketRows[name]; ok {
+		return b.system
+	}
+	return name
+}
+
+// systemRow is the system list row for system, or for a split system the
+// row of its open bucket (or else its first); -1 if it isn't listed
+func systemRow(system string) int {
+	if currentBucket != nil && currentBucket.system == system {
+		if i := systemIndex(currentBucket.name()); i >= 0 {
+			return i
+		}
+	}
+	if i := systemIndex(system); i >= 0 || !bucketed[system] {
+		return i
+	}
+	for i, name := range systemOrder {
+		if b, ok := bucketRows[name]; ok && b.system == system {
+			return i
+		}
+	}
+	return -1
+}
+
+// recountBuckets rebuilds the system list when a new count for system
+// changes whether it is split or how its buckets are counted, keeping the
+// highlighted row where it still exists
+func recountBuckets(system string, old, n int) {
+	if _, ok := cfg.findSystem(system); !ok || bucketMin <= 0 && !bucketed[system] {
+		return
+	}
+	split := bucketMin > 0 && n > bucketMin
+	if split == bucketed[system] && (!split || n == old) {
+		return
+	}
+	rebuildSystemList()
+}
+
+// rebuildSystemList builds the system list again, keeping the highlighted
+// row, or its system if the row is gone
+func rebuildSystemList() {
+	name := systemAt(systemList.GetCurrentItem())
+	buildSystemList(cfg)
+	i := systemIndex(name)
+	if i < 0 {
+		i = systemRow(realSystem(name))
+	}
+	if i >= 0 {
+		systemList.SetCurrentItem(i)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"sync"
+	"testing"
+)
+
+// countingProvider counts the Games calls made for each system
+type countingProvider struct {
+	FSProvider
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (p *countingProvider) Games(system string) ([]GameEntry, error) {
+	p.mu.Lock()
+	p.calls[system]++
+	p.mu.Unlock()
+	return p.FSProvider.Games(system)
+}
+
+func (p *countingProvider) count(system string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls[system]
+}
+
+// TestBucketsNotRecounted checks building the system list again reuses a
+// split system's buckets instead of listing its games each time
+func TestBucketsNotRecounted(t *testing.T) {
+	oldProvider, oldMin := provider, bucketMin
+	t.Cleanup(func() { provider, bucketMin = oldProvider, oldMin })
+	counting := &countingProvider{calls: map[string]int{}}
+	provider, bucketMin = counting, 2
+	startTestUI(t, "NES/Castlevania.nes", "NES/Metroid.nes", "NES/Zelda.nes", "SNES/Zelda.sfc")
+
+	waitFor(t, "NES to be split", func() bool { return bucketed["NES"] })
+	calls := counting.count("NES")
+	for i := 0; i < 3; i++ {
+		tryUpdate(t, func() {
+			rebuildSystemList()
+			setSystemCount("SNES", i)
+		})
+	}
+	tryUpdate(t, func() {
+		if !bucketed["NES"] {
+			t.Error("NES is no longer split")
+		}
+	})
+	if n := counting.count("NES") - calls; n != 0 {
+		t.Errorf("rebuilding the system list listed NES %d more times", n)
+	}
+}
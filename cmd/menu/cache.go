@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// romCache remembers scan results per system so reselecting is instant
+var (
+	romCacheMu sync.Mutex
+	romCache   = map[string][]GameEntry{}
+)
+
+// cachedRoms returns a system's scanned ROMs, scanning on first use
+func cachedRoms(system string) ([]GameEntry, error) {
+	return cachedRomsContext(context.Background(), system, nil)
+}
+
+// cachedRomsContext is cachedRoms with a cancellable, observable scan. The
+// lock is not held while scanning so the UI never waits on the disk.
+func cachedRomsContext(ctx context.Context, system string, progress func(int)) ([]GameEntry, error) {
+	romCacheMu.Lock()
+	games, ok := romCache[system]
+	romCacheMu.Unlock()
+	if ok {
+		return games, nil
+	}
+
+	games, err := scanRomsContext(ctx, system, progress)
+	if err != nil {
+		return nil, err
+	}
+	romCacheMu.Lock()
+	romCache[system] = games
+	romCacheMu.Unlock()
+	return games, nil
+}
+
+// isCached reports whether a system's games can be listed without scanning
+func isCached(system string) bool {
+	if system == recentlyAddedSystem {
+		return allCached()
+	}
+	if system == unknownSystem {
+		system = genericKey
+	}
+	if _, ok := cfg.findSystem(system); !ok {
+		return true // virtual systems and unknown names never scan
+	}
+	romCacheMu.Lock()
+	defer romCacheMu.Unlock()
+	_, ok := romCache[system]
+	_, generic := romCache[genericKey]
+	return ok && generic
+}
+
+// refreshCache drops a system's cached scan, checks again whether it is
+// available and reloads it if it is shown
+func refreshCache(system string) {
+	romCacheMu.Lock()
+	delete(romCache, system)
+	delete(romCache, genericKey)
+	romCacheMu.Unlock()
+	resolveAvailability(system)
+	refreshSystemLabel(system)
+
+	if system == currentSystem {
+		loadGames(app, system)
+	}
+}
+
+// refreshKey rescans the current system on the refresh key ('r'), or the
+// highlighted one from the system list
+func refreshKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Refresh.Matches(event) {
+		return event
+	}
+	system := currentSystem
+	if app.GetFocus() == systemList {
+		system = realSystem(systemAt(systemList.GetCurrentItem()))
+	}
+	if system != "" && system != settingsItem {
+		refreshCache(system)
+	}
+	return nil
+}
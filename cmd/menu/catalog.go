@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// GameProvider is a catalog backend: the systems on offer, the games listed
+// for each of them, whether listing them needs a slow scan, and why a system
+// has nowhere to list games from
+type GameProvider interface {
+	Systems() []SystemConfig
+	Games(system string) ([]GameEntry, error)
+	Cached(system string) bool
+	Unavailable(sys SystemConfig) string
+}
+
+// scanner is a GameProvider whose listing can be cancelled and reports how
+// many games it has found so far
+type scanner interface {
+	Scan(ctx context.Context, system string, progress func(int)) ([]GameEntry, error)
+}
+
+// FSProvider is the GameProvider backed by the config, ROM folder scans and
+// saved lists
+type FSProvider struct{}
+
+// Systems returns the configured systems
+func (FSProvider) Systems() []SystemConfig {
+	return cfg.Systems
+}
+
+// Games returns a system's games, see gamesFor
+func (FSProvider) Games(system string) ([]GameEntry, error) {
+	return gamesFor(system)
+}
+
+// Cached reports whether a system is listed without scanning, see isCached
+func (FSProvider) Cached(system string) bool {
+	return isCached(system)
+}
+
+// Unavailable says why a system without configured games has nothing to
+// scan: its games folder is missing. It is "" otherwise.
+func (FSProvider) Unavailable(sys SystemConfig) string {
+	if len(sys.Games) > 0 {
+		return ""
+	}
+	if info, err := os.Stat(sys.romDir()); err != nil || !info.IsDir() {
+		return sys.romDir() + " does not exist"
+	}
+	return ""
+}
+
+// Scan returns a system's games, see gamesForContext
+func (FSProvider) Scan(ctx context.Context, system string, progress func(int)) ([]GameEntry, error) {
+	return gamesForContext(ctx, system, progress)
+}
+
+// provider is where the menu gets its games from
+var provider GameProvider = FSProvider{}
+
+// scanGames lists a system's games from the provider, cancellably when the
+// provider supports it
+func scanGames(ctx context.Context, system string, progress func(int)) ([]GameEntry, error) {
+	if s, ok := provider.(scanner); ok {
+		return s.Scan(ctx, system, progress)
+	}
+	games, err := provider.Games(system)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if progress != nil {
+		progress(len(games))
+	}
+	return games, err
+}
+
+// gamesFor returns the games of any system, virtual ones included. It only
+// touches the config and caches, never tview, so headless mode can use it.
+func gamesFor(system string) ([]GameEntry, error) {
+	return gamesForContext(context.Background(), system, nil)
+}
+
+// gamesForContext is gamesFor with a cancellable, observable scan
+func gamesForContext(ctx context.Context, system string, progress func(int)) ([]GameEntry, error) {
+	if isArcade(system) {
+		return arcadeGames()
+	}
+	switch system {
+	case recentSystem:
+		return append([]GameEntry(nil), recentGames...), nil
+	case favoritesSystem:
+		return append([]GameEntry(nil), favoriteGames...), nil
+	case mostPlayedSystem:
+		return topPlayed(maxMostPlayed), nil
+	case recentlyAddedSystem:
+		return recentlyAddedContext(ctx, progress)
+	case unknownSystem:
+		return genericGames(ctx, unknownSystem)
+	case allSystemsSearch:
+		return searchAll(""), nil
+	case scriptsSystem:
+		return scriptGames()
+	}
+	if path, ok := playlists()[system]; ok {
+		return loadPlaylist(path)
+	}
+	if _, ok := cfg.findSystem(system); !ok {
+		return nil, fmt.Errorf("unknown system %q", system)
+	}
+	return systemGamesContext(ctx, system, progress)
+}
+
+// systemGames returns a system's configured games followed by its scanned
+// ROMs. A missing ROM folder is only an error if nothing is configured.
+func systemGames(system string) ([]GameEntry, error) {
+	return systemGamesContext(context.Background(), system, nil)
+}
+
+// systemGamesContext is systemGames with a cancellable, observable scan
+func systemGamesContext(ctx context.Context, system string, progress func(int)) ([]GameEntry, error) {
+	sys, _ := cfg.findSystem(system)
+	games := append([]GameEntry(nil), sys.Games...)
+	roms, err := cachedRomsContext(ctx, system, progress)
+	if err != nil && ctx.Err() != nil {
+		return nil, err
+	}
+	sorted, genericErr := genericGames(ctx, system)
+	if genericErr != nil {
+		logWarn("%s: %v", genericDir(), genericErr)
+	}
+	games = append(games, sorted...)
+	if err != nil && os.IsNotExist(err) && len(games) > 0 {
+		err = nil
+	}
+	return append(games, roms...), err
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// errNoClipboard means neither a clipboard tool nor the terminal can take
+// the text
+var errNoClipboard = errors.New("no clipboard available")
+
+// clipboardTools are the commands tried in turn, each reading the text on
+// stdin
+var clipboardTools = [][]string{
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"pbcopy"},
+	{"clip.exe"},
+}
+
+// copyToClipboard puts s on the system clipboard with the first clipboard
+// tool installed, or failing that asks the terminal to with OSC 52. The
+// MiSTer's own console has neither, which gives errNoClipboard.
+func copyToClipboard(s string) error {
+	for _, tool := range clipboardTools {
+		path, err := exec.LookPath(tool[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, tool[1:]...)
+		cmd.Stdin = strings.NewReader(s)
+		if err := cmd.Run(); err != nil {
+			logWarn("%s: %v", tool[0], err)
+			continue
+		}
+		return nil
+	}
+	return copyOSC52(s)
+}
+
+// copyOSC52 sends s to the terminal's clipboard. The Linux console ignores
+// the sequence, so it isn't tried there.
+func copyOSC52(s string) error {
+	if term := os.Getenv("TERM"); term == "" || term == "linux" || term == "dumb" {
+		return errNoClipboard
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return errNoClipboard
+	}
+	defer tty.Close()
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(s)) + "\a"
+	if os.Getenv("TMUX") != "" {
+		// tmux passes the sequence on only when wrapped
+		seq = "\x1bPtmux;\x1b" + seq + "\x1b\\"
+	}
+	_, err = tty.WriteString(seq)
+	return err
+}
+
+// copyPathKey copies the highlighted game's absolute path on the copy key
+// ('y'). Where there is no clipboard the path is shown to copy by hand.
+func copyPathKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Copy.Matches(event) {
+		return event
+	}
+	game, ok := highlightedGame()
+	if !ok {
+		return nil
+	}
+	if game.Path == "" {
+		showMessage(app, T("msg.no_path"))
+		return nil
+	}
+	path, err := filepath.Abs(game.Path)
+	if err != nil {
+		path = game.Path
+	}
+	if err := copyToClipboard(path); err != nil {
+		if !errors.Is(err, errNoClipboard) {
+			logWarn("copying path: %v", err)
+		}
+		showMessageFor(app, fmt.Sprintf(T("msg.copy_manually"), path), 0)
+		return nil
+	}
+	flashStatus(T("status.copied"))
+	return nil
+}
@@ -0,0 +1,472 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the menu definition loaded from systems.yaml
+type Config struct {
+	Systems  []SystemConfig `yaml:"systems"`
+	Settings Settings       `yaml:"settings,omitempty"`
+	Titles   TitleRules     `yaml:"titles,omitempty"`
+	Hooks    LaunchHooks    `yaml:"hooks,omitempty"`
+}
+
+// Settings are the runtime options editable from the Settings screen
+type Settings struct {
+	Sort     string `yaml:"sort,omitempty"`
+	ShowAll  bool   `yaml:"show_all,omitempty"`
+	Theme    string `yaml:"theme,omitempty"`
+	GamesDir string `yaml:"games_dir,omitempty"`
+	NewDays  int    `yaml:"new_days,omitempty"`
+
+	// MessageSeconds closes informational modals after this long; 0 keeps
+	// them until OK is pressed
+	MessageSeconds int `yaml:"message_seconds,omitempty"`
+
+	// GroupSystems lists systems under their category headers
+	GroupSystems bool `yaml:"group_systems,omitempty"`
+
+	// ShowPaths lists games by file path instead of title
+	ShowPaths bool `yaml:"show_paths,omitempty"`
+
+	// SeparatorMin is how long a name-sorted list must be to get letter
+	// separators; negative turns them off
+	SeparatorMin int `yaml:"separator_min,omitempty"`
+
+	// IdleSeconds without input trigger IdleAction ("dim" or "random");
+	// 0 never
+	IdleSeconds int    `yaml:"idle_seconds,omitempty"`
+	IdleAction  string `yaml:"idle_action,omitempty"`
+
+	// Panes are the widths of the systems, games and details panes
+	Panes []int `yaml:"panes,omitempty,flow"`
+
+	// Secondary is the template for the line under each game, such as
+	// "{region} · {size}"
+	Secondary string `yaml:"secondary,omitempty"`
+
+	// LetterKeys makes unbound letters "jump" to titles or "filter" by them
+	LetterKeys string `yaml:"letter_keys,omitempty"`
+
+	// HistoryMax is how many launches the history keeps
+	HistoryMax int `yaml:"history_max,omitempty"`
+
+	// CoverCache bounds the megabytes of decoded cover images kept in
+	// memory; negative keeps none
+	CoverCache int `yaml:"cover_cache,omitempty"`
+
+	// BucketMin splits systems with more games than this into letter
+	// ranges in the system list; 0 never does
+	BucketMin int `yaml:"bucket_min,omitempty"`
+
+	// MaxItems caps the rows an unfiltered game list starts with, adding a
+	// row that shows the rest; 0 lists everything
+	MaxItems int `yaml:"max_items,omitempty"`
+
+	// NoIcons leaves the system icons off the game list
+	NoIcons bool `yaml:"no_icons,omitempty"`
+
+	// GameView shows the games as a "list" or a "grid"
+	GameView string `yaml:"game_view,omitempty"`
+
+	// InfoFirst shows a game's cover and details, with a Launch button,
+	// before launching it
+	InfoFirst bool `yaml:"info_first,omitempty"`
+
+	// AutoHideSystems slides the system pane away once a system is opened
+	AutoHideSystems bool `yaml:"auto_hide_systems,omitempty"`
+
+	// QueueSeconds is how long each game in the launch queue runs
+	QueueSeconds int `yaml:"queue_seconds,omitempty"`
+
+	// Screenshots shows a game's newest MiSTer screenshot in place of its
+	// cover art; ScreenshotsDir is where they are saved
+	Screenshots    bool   `yaml:"screenshots,omitempty"`
+	ScreenshotsDir string `yaml:"screenshots_dir,omitempty"`
+
+	// RememberScroll returns to the row each system was left on
+	RememberScroll bool `yaml:"remember_scroll,omitempty"`
+}
+
+// SystemConfig describes one system entry and its games
+type SystemConfig struct {
+	Name        string      `yaml:"name"`
+	Description string      `yaml:"description,omitempty"`
+	Hotkey      string      `yaml:"hotkey,omitempty"`
+	Dir         string      `yaml:"dir,omitempty"`
+	Core        string      `yaml:"core,omitempty"`
+	Category    string      `yaml:"category,omitempty"`
+	Icon        string      `yaml:"icon,omitempty"`
+	Sort        string      `yaml:"sort,omitempty"`
+	Dat         string      `yaml:"dat,omitempty"`
+	Extensions  []string    `yaml:"extensions,omitempty"`
+	Games       []GameEntry `yaml:"games,omitempty"`
+
+	// Include and Exclude are file name globs narrowing what a scan lists
+	// beyond the extensions; see matchesFilters
+	Include []string `yaml:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty"`
+
+	// Boot lists ways to start the core; with any, launching asks which
+	Boot []BootOption `yaml:"boot,omitempty"`
+
+	// line is where the entry starts in the systems file, for messages
+	line int
+}
+
+// GameEntry is a single launchable game
+type GameEntry struct {
+	Title   string `yaml:"title" json:"title"`
+	Command string `yaml:"command,omitempty" json:"command,omitempty"`
+	Path    string `yaml:"path,omitempty" json:"path,omitempty"`
+	System  string `yaml:"-" json:"system"`
+
+	// Core and Args override the system's core and add launch arguments
+	Core string   `yaml:"core,omitempty" json:"core,omitempty"`
+	Args []string `yaml:"args,omitempty" json:"args,omitempty"`
+
+	// Folder is the subfolder the ROM was found in, relative to the system's
+	// folder and slash-separated; "" for the top level
+	Folder string `yaml:"-" json:"folder,omitempty"`
+
+	// ModTime is the ROM file's modification time, set by the scan
+	ModTime time.Time `yaml:"-" json:"-"`
+
+	// Discs are every disc of a multi-disc set, set by groupDiscs
+	Discs []GameEntry `yaml:"-" json:"-"`
+}
+
+// rawConfig keeps entries as nodes so a bad one can be skipped on its own
+type rawConfig struct {
+	Systems  []yaml.Node `yaml:"systems"`
+	Settings yaml.Node   `yaml:"settings"`
+	Titles   yaml.Node   `yaml:"titles"`
+	Hooks    yaml.Node   `yaml:"hooks"`
+}
+
+// Known keys at each level of the systems file
+var (
+	topFields    = []string{"systems", "settings", "titles", "hooks"}
+	systemFields = []string{"name", "description", "hotkey", "dir", "core", "category", "icon", "sort", "dat", "extensions", "include", "exclude", "games", "boot"}
+	gameFields   = []string{"title", "command", "path", "core", "args"}
+)
+
+// ConfigProblem is one thing wrong with the systems file. Fatal problems
+// stop the menu; the rest are only warnings.
+type ConfigProblem struct {
+	Line  int
+	Msg   string
+	Fatal bool
+}
+
+// ConfigErrors lists every problem found in a systems file
+type ConfigErrors struct {
+	Path     string
+	Problems []ConfigProblem
+}
+
+func (e *ConfigErrors) Error() string {
+	var b strings.Builder
+	for i, p := range e.Problems {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(p.String(e.Path))
+	}
+	return b.String()
+}
+
+// String formats a problem as "path line N: msg"
+func (p ConfigProblem) String(path string) string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s line %d: %s", path, p.Line, p.Msg)
+	}
+	return path + ": " + p.Msg
+}
+
+// add records a problem; fatal ones make the config unusable
+func (e *ConfigErrors) add(line int, fatal bool, format string, args ...interface{}) {
+	e.Problems = append(e.Problems, ConfigProblem{Line: line, Msg: fmt.Sprintf(format, args...), Fatal: fatal})
+}
+
+// Fatal reports whether any problem is fatal
+func (e *ConfigErrors) Fatal() bool {
+	for _, p := range e.Problems {
+		if p.Fatal {
+			return true
+		}
+	}
+	return false
+}
+
+// orNil returns e as an error, or nil when nothing was found
+func (e *ConfigErrors) orNil() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+// defaultConfig is the built-in menu used when no systems.yaml exists
+func defaultConfig() *Config {
+	return &Config{Systems: []SystemConfig{
+		{Name: "NES", Description: "Nintendo Entertainment System", Hotkey: "n", Category: "Consoles"},
+		{Name: "SNES", Description: "Super Nintendo", Hotkey: "s", Category: "Consoles"},
+		{Name: "Genesis", Description: "Sega Genesis / Mega Drive", Hotkey: "g", Category: "Consoles"},
+	}}
+}
+
+// LoadConfig reads the systems file, falling back to defaults if it is
+// missing. Bad entries are skipped and reported together in a *ConfigErrors
+// returned alongside the config.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return defaultConfig(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var raw rawConfig
+	if err := doc.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	problems := &ConfigErrors{Path: path}
+	if len(doc.Content) > 0 {
+		checkFields(problems, doc.Content[0], topFields)
+	}
+	cfg := &Config{}
+	if raw.Settings.Kind != 0 {
+		if err := raw.Settings.Decode(&cfg.Settings); err != nil {
+			problems.add(raw.Settings.Line, false, "ignoring settings: %v", err)
+		}
+	}
+	if raw.Titles.Kind != 0 {
+		if err := raw.Titles.Decode(&cfg.Titles); err != nil {
+			problems.add(raw.Titles.Line, false, "ignoring titles: %v", err)
+		}
+		for _, name := range cfg.Titles.Rules {
+			if _, ok := titleRules[name]; !ok {
+				problems.add(raw.Titles.Line, false, "unknown title rule %q", name)
+			}
+		}
+	}
+	if raw.Hooks.Kind != 0 {
+		if err := raw.Hooks.Decode(&cfg.Hooks); err != nil {
+			problems.add(raw.Hooks.Line, false, "ignoring hooks: %v", err)
+		}
+	}
+	for _, node := range raw.Systems {
+		node := node
+		checkFields(problems, &node, systemFields)
+		var sys SystemConfig
+		if err := node.Decode(&sys); err != nil {
+			problems.add(node.Line, true, "bad system: %v", err)
+			continue
+		}
+		if sys.Name == "" {
+			problems.add(node.Line, true, "system has no name")
+			continue
+		}
+		if sys.Hotkey != "" && utf8.RuneCountInString(sys.Hotkey) != 1 {
+			problems.add(node.Line, true, "%s: hotkey %q must be a single character", sys.Name, sys.Hotkey)
+			continue
+		}
+		sys.line = node.Line
+		sys.Boot = validBoot(problems, sys)
+		sys.Include = validGlobs(problems, sys, "include", sys.Include)
+		sys.Exclude = validGlobs(problems, sys, "exclude", sys.Exclude)
+		sys.Extensions = dottedExtensions(sys.Extensions)
+		sys.Games = validGames(problems, &node, sys)
+		cfg.Systems = append(cfg.Systems, sys)
+	}
+	return cfg, problems.orNil()
+}
+
+// validGames drops game entries that have no title, reporting them and any
+// unknown fields against the game's own line
+func validGames(problems *ConfigErrors, node *yaml.Node, sys SystemConfig) []GameEntry {
+	var gameNodes []*yaml.Node
+	if v := mappingValue(node, "games"); v != nil && v.Kind == yaml.SequenceNode {
+		gameNodes = v.Content
+	}
+
+	games := sys.Games[:0]
+	for i, g := range sys.Games {
+		line := node.Line
+		if i < len(gameNodes) {
+			line = gameNodes[i].Line
+			checkFields(problems, gameNodes[i], gameFields)
+		}
+		if g.Title == "" {
+			problems.add(line, true, "%s: game has no title", sys.Name)
+			continue
+		}
+		g.System = sys.Name
+		games = append(games, g)
+	}
+	return games
+}
+
+// validBoot drops boot options without a name, which couldn't be picked
+func validBoot(problems *ConfigErrors, sys SystemConfig) []BootOption {
+	opts := sys.Boot[:0]
+	for _, opt := range sys.Boot {
+		if opt.Name == "" {
+			problems.add(sys.line, false, "%s: ignoring boot option with no name", sys.Name)
+			continue
+		}
+		opts = append(opts, opt)
+	}
+	return opts
+}
+
+// validGlobs drops patterns from a system's include or exclude list that
+// aren't valid globs
+func validGlobs(problems *ConfigErrors, sys SystemConfig, field string, globs []string) []string {
+	valid := globs[:0]
+	for _, g := range globs {
+		if _, err := path.Match(g, ""); err != nil {
+			problems.add(sys.line, false, "%s: ignoring %s pattern %q: %v", sys.Name, field, g, err)
+			continue
+		}
+		valid = append(valid, g)
+	}
+	return valid
+}
+
+// dottedExtensions adds the leading dot filepath.Ext returns to entries
+// written without one, so "nes" matches like ".nes"
+func dottedExtensions(exts []string) []string {
+	for i, e := range exts {
+		if e != "" && !strings.HasPrefix(e, ".") {
+			exts[i] = "." + e
+		}
+	}
+	return exts
+}
+
+// checkFields reports keys of a mapping that aren't in known
+func checkFields(problems *ConfigErrors, node *yaml.Node, known []string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		found := false
+		for _, k := range known {
+			if key.Value == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems.add(key.Line, true, "unknown field %q (expected one of %s)", key.Value, strings.Join(known, ", "))
+		}
+	}
+}
+
+// mappingValue returns the value under key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// Validate checks the loaded systems against each other and the disk:
+// hotkeys must be unique. A missing ROM folder only leaves its system
+// unavailable and a missing media folder only means no covers, so both are
+// warnings.
+func (c *Config) Validate() error {
+	problems := &ConfigErrors{Path: configPath}
+	hotkeys := map[rune]SystemConfig{}
+	for _, sys := range c.Systems {
+		if r := sys.hotkeyRune(); r != 0 {
+			if other, ok := hotkeys[r]; ok {
+				problems.add(sys.line, true, "%s: hotkey %q is already used by %s (line %d)", sys.Name, r, other.Name, other.line)
+			} else {
+				hotkeys[r] = sys
+			}
+		}
+		if _, ok := parseSortMode(sys.Sort); sys.Sort != "" && !ok {
+			problems.add(sys.line, false, "%s: unknown sort %q, using the default", sys.Name, sys.Sort)
+		}
+		if sys.Dat != "" {
+			if _, err := os.Stat(sys.Dat); err != nil {
+				problems.add(sys.line, false, "%s: dat %s: %v", sys.Name, sys.Dat, err)
+			}
+		}
+		if sys.Dir != "" {
+			if info, err := os.Stat(sys.Dir); err != nil || !info.IsDir() {
+				problems.add(sys.line, false, "%s: dir %s is not a directory, listed as unavailable", sys.Name, sys.Dir)
+			}
+		}
+	}
+	if c.Settings.Panes != nil {
+		if err := checkPanes(c.Settings.Panes); err != nil {
+			problems.add(0, false, "settings: %v, using %v", err, defaultPaneWeights)
+		}
+	}
+	for _, m := range secondaryToken.FindAllStringSubmatch(c.Settings.Secondary, -1) {
+		if _, ok := secondaryFields[m[1]]; !ok {
+			problems.add(0, false, "settings: secondary: unknown field %s, shown empty", m[0])
+		}
+	}
+	if info, err := os.Stat(mediaDir()); err != nil || !info.IsDir() {
+		problems.add(0, false, "no media folder at %s, covers are disabled", mediaDir())
+	}
+	return problems.orNil()
+}
+
+// configDir is where state files are kept, next to the systems file
+func configDir() string {
+	return filepath.Dir(configPath)
+}
+
+// romDir returns the directory scanned for a system's ROMs
+func (s SystemConfig) romDir() string {
+	if s.Dir != "" {
+		return s.Dir
+	}
+	return filepath.Join(gamesDir, s.Name)
+}
+
+// hotkeyRune returns the shortcut for a system, or 0 for none
+func (s SystemConfig) hotkeyRune() rune {
+	r, _ := utf8.DecodeRuneInString(s.Hotkey)
+	if r == utf8.RuneError {
+		return 0
+	}
+	return r
+}
+
+// findSystem looks up a system by name
+func (c *Config) findSystem(name string) (SystemConfig, bool) {
+	for _, s := range c.Systems {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return SystemConfig{}, false
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfigDotsExtensions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "systems.yaml")
+	data := "systems:\n  - name: NES\n    extensions: [nes, .fds, UNF]\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{".nes", ".fds", ".UNF"}
+	if got := loaded.Systems[0].Extensions; !reflect.DeepEqual(got, want) {
+		t.Errorf("extensions = %q, want %q", got, want)
+	}
+}
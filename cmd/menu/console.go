@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// consoleKey opens and closes the developer console with -dev
+const consoleKey = tcell.KeyCtrlO
+
+var (
+	// devMode enables the developer console, from -dev
+	devMode bool
+
+	// console is the developer console while it is open, or nil
+	console tview.Primitive
+	// consoleHistory holds the commands sent this run, oldest first
+	consoleHistory []string
+)
+
+// consoleToggleKey opens the console on consoleKey, or closes it if open
+func consoleToggleKey(event *tcell.EventKey) *tcell.EventKey {
+	if !devMode || event.Key() != consoleKey {
+		return event
+	}
+	if console != nil {
+		nav.close(console)
+		console = nil
+	} else if nav.empty() {
+		showConsole()
+	}
+	return nil
+}
+
+// showConsole opens a prompt that sends raw MiSTer commands through the
+// active launcher and logs the outcome of each
+func showConsole() {
+	output := tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
+	fmt.Fprintf(output, "[gray]Commands go to %s. Up/down recall earlier ones; Esc closes.[-]\n", tview.Escape(launcherName()))
+
+	input := tview.NewInputField().SetLabel("> ")
+	pos := len(consoleHistory)
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			if pos > 0 {
+				pos--
+				input.SetText(consoleHistory[pos])
+			}
+			return nil
+		case tcell.KeyDown:
+			if pos < len(consoleHistory) {
+				pos++
+			}
+			if pos < len(consoleHistory) {
+				input.SetText(consoleHistory[pos])
+			} else {
+				input.SetText("")
+			}
+			return nil
+		}
+		return event
+	})
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			nav.close(console)
+			console = nil
+			return
+		}
+		cmd := input.GetText()
+		if key != tcell.KeyEnter || cmd == "" {
+			return
+		}
+		input.SetText("")
+		consoleHistory = append(consoleHistory, cmd)
+		pos = len(consoleHistory)
+
+		fmt.Fprintf(output, "> %s\n", tview.Escape(cmd))
+		if err := launcher.Launch(GameEntry{Title: "console", Command: cmd}); err != nil {
+			logWarn("console %q: %v", cmd, err)
+			fmt.Fprintf(output, "[red]%s[-]\n", tview.Escape(err.Error()))
+			return
+		}
+		logInfo("console sent %q", cmd)
+		output.Write([]byte("[green]sent[-]\n"))
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(output, 0, 1, false).
+		AddItem(input, 1, 0, true)
+	layout.SetBorder(true).SetTitle(" Console ")
+	console = layout
+	nav.push(layout)
+}
+
+// launcherName describes where commands go, for the console banner
+func launcherName() string {
+	switch l := launcher.(type) {
+	case *DeviceLauncher:
+		return l.Path
+	case *RemoteLauncher:
+		return cmdDevice + " on " + l.User + "@" + l.Addr
+	case *DryRunLauncher:
+		return "nowhere (dry run)"
+	}
+	return "the launcher"
+}
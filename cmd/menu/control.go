@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// controlRequest is one command read from the control socket
+type controlRequest struct {
+	Action string `json:"action"`
+	System string `json:"system"`
+	Game   string `json:"game"`
+}
+
+// controlResponse answers a request; Error is set when it failed
+type controlResponse struct {
+	OK    bool       `json:"ok"`
+	Error string     `json:"error,omitempty"`
+	Game  *GameEntry `json:"game,omitempty"`
+}
+
+// startControl listens for JSON commands on a Unix socket at path, one
+// request per line, replacing a socket left behind by an earlier run
+func startControl(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					logError("control socket: %v", err)
+				}
+				return
+			}
+			go serveControl(conn)
+		}
+	}()
+	logInfo("control socket listening on %s", path)
+	return ln, nil
+}
+
+// stopControl closes the listener, which also removes its socket file
+func stopControl(ln net.Listener) {
+	if err := ln.Close(); err != nil {
+		logWarn("control socket: %v", err)
+	}
+}
+
+// serveControl answers requests on conn until it closes. A request that
+// isn't valid JSON gets an error and ends the connection, since the rest of
+// the stream can't be trusted.
+func serveControl(conn net.Conn) {
+	defer conn.Close()
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		var req controlRequest
+		if err := dec.Decode(&req); err != nil {
+			if err != io.EOF {
+				enc.Encode(controlResponse{Error: "malformed request: " + err.Error()})
+			}
+			return
+		}
+		resp := handleControl(req)
+		// A client that stops reading can't hold the connection forever
+		conn.SetWriteDeadline(time.Now().Add(networkTimeout))
+		enc.Encode(resp)
+	}
+}
+
+// handleControl carries out one request
+func handleControl(req controlRequest) controlResponse {
+	switch req.Action {
+	case "launch":
+		game, err := findGame(req.System, req.Game)
+		if err != nil {
+			return controlResponse{Error: err.Error()}
+		}
+		if err := launchExternal(game); err != nil {
+			return controlResponse{Error: err.Error(), Game: &game}
+		}
+		return controlResponse{OK: true, Game: &game}
+	case "":
+		return controlResponse{Error: "missing action"}
+	}
+	return controlResponse{Error: fmt.Sprintf("unknown action %q", req.Action)}
+}
+
+// findGame looks up a game of a configured system by its title, shown or raw,
+// ignoring case
+func findGame(system, title string) (GameEntry, error) {
+	if _, ok := cfg.findSystem(system); !ok {
+		return GameEntry{}, fmt.Errorf("unknown system %q", system)
+	}
+	if title == "" {
+		return GameEntry{}, errors.New("missing game")
+	}
+	games, err := provider.Games(system)
+	if err != nil {
+		return GameEntry{}, err
+	}
+	for _, g := range games {
+		if strings.EqualFold(g.Title, title) || strings.EqualFold(displayTitle(g), title) {
+			return g, nil
+		}
+	}
+	return GameEntry{}, fmt.Errorf("%s: no game %q", system, title)
+}
+
+// launchExternal launches a game asked for over the control socket, off the
+// UI goroutine and within launchTimeout, and then moves the selection to it
+// so the screen shows what is running. Errors go back to the caller rather
+// than into a modal.
+func launchExternal(game GameEntry) error {
+	// Nobody is at the screen to pick a boot option, so take the first
+	if opts := bootOptionsFor(game.System); len(opts) > 0 {
+		game = withBoot(game, opts[0])
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), launchTimeout())
+	defer cancel()
+	cmd, err := launchGameContext(ctx, game)
+	if err != nil {
+		logError("launching %s (%s) for control socket: %v", game.Title, game.System, err)
+		return err
+	}
+	ui.update(func() { showExternalLaunch(game, cmd) })
+	return nil
+}
+
+// showExternalLaunch selects a game launched over the control socket as cmd
+// and records the launch
+func showExternalLaunch(game GameEntry, cmd string) {
+	if i := systemRow(game.System); i >= 0 && nav.empty() {
+		systemList.SetCurrentItem(i)
+		if currentSystem == game.System {
+			selectGame(game)
+		} else {
+			loadGamesThen(game.System, func() { selectGame(game) })
+		}
+	}
+	gameLaunched(game, cmd)
+}
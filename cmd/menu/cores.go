@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// misterRoot is the MiSTer SD card mount
+const misterRoot = "/media/fat"
+
+// coreDirs are the folders under misterRoot that hold core RBFs
+var coreDirs = []string{"_Console", "_Computer", "_Other", "_Arcade", "_Utility"}
+
+// coreName is the RBF base name for a system: its "core" setting or its name
+func (s SystemConfig) coreName() string {
+	if s.Core != "" {
+		return s.Core
+	}
+	return s.Name
+}
+
+var (
+	deviceOnce sync.Once
+	isDevice   bool
+)
+
+// onDevice reports whether we are running on a MiSTer, judged by its core folders
+func onDevice() bool {
+	deviceOnce.Do(func() {
+		for _, d := range coreDirs {
+			if info, err := os.Stat(filepath.Join(misterRoot, d)); err == nil && info.IsDir() {
+				isDevice = true
+				return
+			}
+		}
+	})
+	return isDevice
+}
+
+// coreAvailable reports whether a system's core RBF is installed. Off the
+// MiSTer there is nothing to check, so every system counts as available.
+func coreAvailable(system string) bool {
+	if !onDevice() {
+		return true
+	}
+	_, err := resolveCore(system)
+	return err == nil
+}
+
+// coreInstalled reports whether an RBF named name exists in a core folder
+func coreInstalled(name string) bool {
+	return corePath(name) != ""
+}
+
+// corePath finds the RBF file for a core name, or "" if none is installed
+func corePath(name string) string {
+	path, _ := newestCore(name)
+	return path
+}
+
+// resolveCore finds the RBF a system's games load with
+func resolveCore(system string) (string, error) {
+	sys, ok := cfg.findSystem(system)
+	if !ok {
+		return "", fmt.Errorf("unknown system %s", system)
+	}
+	return newestCore(sys.coreName())
+}
+
+// coreDate matches the build date cores ship with, e.g. NES_20240101.rbf
+var coreDate = regexp.MustCompile(`_(\d{8})\.rbf$`)
+
+// newestCore looks through every core folder for name's RBFs and picks the
+// latest build by its date suffix; an undated name.rbf is only used when no
+// dated build exists
+func newestCore(name string) (string, error) {
+	best, bestDate := "", ""
+	for _, d := range coreDirs {
+		dir := filepath.Join(misterRoot, d)
+		if _, err := os.Stat(filepath.Join(dir, name+".rbf")); err == nil && best == "" {
+			best = filepath.Join(dir, name+".rbf")
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, name+"_*.rbf"))
+		for _, m := range matches {
+			// The suffix must be just the date, so NES_Extra_20240101 isn't NES
+			date := coreDate.FindStringSubmatch(m)
+			if date == nil || filepath.Base(m) != name+date[0] {
+				continue
+			}
+			if date[1] > bestDate {
+				best, bestDate = m, date[1]
+			}
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no %s core installed under %s", name, misterRoot)
+	}
+	return best, nil
+}
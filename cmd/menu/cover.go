@@ -0,0 +1,305 @@
+package main
+
+// Cover art is drawn straight onto the terminal, outside of tview. The details
+// column reserves an empty coverBox; after every frame drawCover locks those
+// cells so tcell never paints over them, flushes the frame and then writes the
+// image escape sequence into the box. When the highlighted game changes the
+// old image is erased (kitty deletes it, sixel needs a full screen resync) and
+// the new one is drawn in its place.
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // cover art is commonly JPEG
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+type imageProtocol int
+
+const (
+	protoNone imageProtocol = iota
+	protoKitty
+	protoSixel
+)
+
+// coverRows is the height of the cover art region in terminal rows
+const coverRows = 12
+
+var (
+	// imageProto is the graphics protocol the terminal speaks
+	imageProto = detectImageProtocol()
+
+	// coverBox is the empty region images are painted over
+	coverBox *tview.Box
+
+	// coverPath is the image for the highlighted game; drawnCover and
+	// drawnRect describe what is currently on the terminal
+	coverPath  string
+	drawnCover string
+	drawnRect  [4]int
+)
+
+// detectImageProtocol guesses image support from the environment;
+// PEEPER_IMAGES=kitty|sixel|none overrides the guess
+func detectImageProtocol() imageProtocol {
+	switch os.Getenv("PEEPER_IMAGES") {
+	case "kitty":
+		return protoKitty
+	case "sixel":
+		return protoSixel
+	case "none":
+		return protoNone
+	}
+
+	term := os.Getenv("TERM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty",
+		os.Getenv("TERM_PROGRAM") == "WezTerm", os.Getenv("TERM_PROGRAM") == "ghostty":
+		return protoKitty
+	case strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"),
+		strings.Contains(term, "sixel"), os.Getenv("TERM_PROGRAM") == "iTerm.app":
+		return protoSixel
+	}
+	return protoNone
+}
+
+// mediaDir holds cover art as media/<system>/<rom name>.png
+func mediaDir() string {
+	return filepath.Join(gamesDir, "media")
+}
+
+// coverFor finds the cover image for a game, or "" if there is none
+func coverFor(entry GameEntry) string {
+	if entry.Path == "" {
+		return ""
+	}
+	base := strings.TrimSuffix(filepath.Base(entry.Path), filepath.Ext(entry.Path))
+	for _, ext := range []string{".png", ".jpg", ".jpeg"} {
+		p := filepath.Join(mediaDir(), entry.System, base+ext)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// drawCover keeps the terminal image in sync with coverPath after each frame
+func drawCover(screen tcell.Screen) {
+	if imageProto == protoNone {
+		return
+	}
+	tty, ok := screen.Tty()
+	if !ok {
+		return
+	}
+
+	box, want := coverBox, coverPath
+	if infoShown() && infoCoverBox != nil {
+		box, want = infoCoverBox, infoCover
+	} else if !coverShown() {
+		want = ""
+	}
+	x, y, w, h := box.GetInnerRect()
+	rect := [4]int{x, y, w, h}
+	if want == drawnCover && rect == drawnRect {
+		return
+	}
+
+	// Erase the previous image before drawing the next one
+	screen.LockRegion(drawnRect[0], drawnRect[1], drawnRect[2], drawnRect[3], false)
+	if drawnCover != "" {
+		if imageProto == protoKitty {
+			io.WriteString(tty, "\x1b_Ga=d,q=2\x1b\\")
+		}
+		screen.Sync()
+	}
+	drawnCover, drawnRect = want, rect
+	if want == "" || w <= 0 || h <= 0 {
+		return
+	}
+
+	screen.LockRegion(x, y, w, h, true)
+	screen.Show()
+	renderCover(tty, box, want)
+}
+
+// coverShown reports whether the cover box is on screen, which it isn't in
+// the narrow layout or with the details pane hidden
+func coverShown() bool {
+	return mainShown() && !layoutNarrow && paneWeights[2] > 0
+}
+
+// renderCover paints imgPath into box, or "no image" if it can't
+func renderCover(w io.Writer, box *tview.Box, imgPath string) error {
+	x, y, cols, rows := box.GetInnerRect()
+	fmt.Fprintf(w, "\x1b7\x1b[%d;%dH", y+1, x+1)
+	defer io.WriteString(w, "\x1b8")
+
+	img, err := cachedImage(imgPath)
+	if err == nil && imageProto == protoNone {
+		err = fmt.Errorf("terminal has no image support")
+	}
+	if err != nil {
+		io.WriteString(w, "no image")
+		return err
+	}
+
+	cellW, cellH := 8, 16
+	if tw, ok := w.(tcell.Tty); ok {
+		if ws, err := tw.WindowSize(); err == nil {
+			if cw, ch := ws.CellDimensions(); cw > 0 && ch > 0 {
+				cellW, cellH = cw, ch
+			}
+		}
+	}
+	img = fitImage(img, cols*cellW, rows*cellH)
+
+	if imageProto == protoKitty {
+		return writeKitty(w, img, cols, rows)
+	}
+	return writeSixel(w, img)
+}
+
+// decodeImage loads a PNG or JPEG file
+func decodeImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
+
+// fitImage scales img down (nearest neighbour) to fit within maxW x maxH
+func fitImage(img image.Image, maxW, maxH int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= maxW && b.Dy() <= maxH {
+		return img
+	}
+	scale := float64(maxW) / float64(b.Dx())
+	if s := float64(maxH) / float64(b.Dy()); s < scale {
+		scale = s
+	}
+	w, h := int(float64(b.Dx())*scale), int(float64(b.Dy())*scale)
+	if w < 1 || h < 1 {
+		return img
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for dy := 0; dy < h; dy++ {
+		sy := b.Min.Y + int(float64(dy)/scale)
+		for dx := 0; dx < w; dx++ {
+			out.Set(dx, dy, img.At(b.Min.X+int(float64(dx)/scale), sy))
+		}
+	}
+	return out
+}
+
+// writeKitty sends img as PNG using the kitty graphics protocol
+func writeKitty(w io.Writer, img image.Image, cols, rows int) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	const chunk = 4096
+	for first := true; len(data) > 0; first = false {
+		n := len(data)
+		if n > chunk {
+			n = chunk
+		}
+		more := 0
+		if n < len(data) {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(w, "\x1b_Ga=T,f=100,q=2,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, data[:n])
+		} else {
+			fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, data[:n])
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// writeSixel sends img as sixel graphics quantised to a 6x6x6 colour cube
+func writeSixel(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "\x1bPq\"1;1;%d;%d", b.Dx(), b.Dy())
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(&out, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+	}
+
+	index := make([]int, b.Dx())
+	for top := b.Min.Y; top < b.Max.Y; top += 6 {
+		// Gather each colour's six-pixel column masks for this band
+		masks := map[int][]byte{}
+		var order []int
+		for k := 0; k < 6 && top+k < b.Max.Y; k++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				index[x-b.Min.X] = cubeIndex(img.At(x, top+k))
+			}
+			for x, c := range index {
+				m, ok := masks[c]
+				if !ok {
+					m = make([]byte, b.Dx())
+					masks[c] = m
+					order = append(order, c)
+				}
+				m[x] |= 1 << uint(k)
+			}
+		}
+
+		for i, c := range order {
+			if i > 0 {
+				out.WriteByte('$')
+			}
+			fmt.Fprintf(&out, "#%d", c)
+			writeSixelRuns(&out, masks[c])
+		}
+		out.WriteByte('-')
+	}
+	out.WriteString("\x1b\\")
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// writeSixelRuns run-length encodes one colour's row of sixels
+func writeSixelRuns(out *bytes.Buffer, mask []byte) {
+	for x := 0; x < len(mask); {
+		run := 1
+		for x+run < len(mask) && mask[x+run] == mask[x] {
+			run++
+		}
+		ch := byte(63 + mask[x])
+		if run > 3 {
+			fmt.Fprintf(out, "!%d%c", run, ch)
+		} else {
+			for i := 0; i < run; i++ {
+				out.WriteByte(ch)
+			}
+		}
+		x += run
+	}
+}
+
+// cubeIndex maps a colour onto the 216 entry sixel palette
+func cubeIndex(c color.Color) int {
+	r, g, b, _ := c.RGBA()
+	level := func(v uint32) int { return int((v*5 + 0x7fff) / 0xffff) }
+	return level(r)*36 + level(g)*6 + level(b)
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"container/list"
+	"image"
+	"sync"
+)
+
+const (
+	// defaultCoverCache is how many megabytes of decoded covers are kept by
+	// default
+	defaultCoverCache = 32
+	// coverPrefetch is how many rows above and below the selection have
+	// their covers decoded ahead of time
+	coverPrefetch = 3
+	// minCoverBytes is the least an entry counts for, so failed decodes
+	// are bounded too
+	minCoverBytes = 4 << 10
+)
+
+// coverCacheBytes bounds the pixel bytes of the decoded covers kept in
+// memory; 0 turns caching and prefetching off
+var coverCacheBytes = defaultCoverCache << 20
+
+// decodedCover is a cached decode: the image, or why there is none
+type decodedCover struct {
+	path string
+	img  image.Image
+	err  error
+	size int // pixel bytes of img
+}
+
+// coverCache keeps the most recently used decoded covers, failures
+// included so a broken file isn't decoded again on every visit
+var coverCache = struct {
+	sync.Mutex
+	order *list.List // of *decodedCover, most recent first
+	items map[string]*list.Element
+	bytes int // total size of the cached covers
+}{order: list.New(), items: map[string]*list.Element{}}
+
+// cachedImage decodes path, or returns the cached result of an earlier decode
+func cachedImage(path string) (image.Image, error) {
+	coverCache.Lock()
+	if e, ok := coverCache.items[path]; ok {
+		coverCache.order.MoveToFront(e)
+		c := e.Value.(*decodedCover)
+		coverCache.Unlock()
+		return c.img, c.err
+	}
+	coverCache.Unlock()
+
+	// Decode unlocked so the UI never waits on a prefetch
+	img, err := decodeImage(path)
+	size := imageBytes(img)
+	if size < minCoverBytes {
+		size = minCoverBytes
+	}
+	storeCover(&decodedCover{path: path, img: img, err: err, size: size})
+	return img, err
+}
+
+// storeCover adds c to the cache, evicting the least recently used covers
+// until they fit in coverCacheBytes. A cover that fills it alone isn't kept.
+func storeCover(c *decodedCover) {
+	coverCache.Lock()
+	defer coverCache.Unlock()
+	if coverCacheBytes <= 0 || c.size > coverCacheBytes {
+		return
+	}
+	if e, ok := coverCache.items[c.path]; ok {
+		coverCache.bytes -= e.Value.(*decodedCover).size
+		e.Value = c
+		coverCache.order.MoveToFront(e)
+	} else {
+		coverCache.items[c.path] = coverCache.order.PushFront(c)
+	}
+	coverCache.bytes += c.size
+	for coverCache.bytes > coverCacheBytes {
+		last := coverCache.order.Back()
+		coverCache.order.Remove(last)
+		old := last.Value.(*decodedCover)
+		delete(coverCache.items, old.path)
+		coverCache.bytes -= old.size
+	}
+}
+
+// imageBytes is how much memory img's pixels take, 0 for none
+func imageBytes(img image.Image) int {
+	switch img := img.(type) {
+	case nil:
+		return 0
+	case *image.RGBA:
+		return len(img.Pix)
+	case *image.NRGBA:
+		return len(img.Pix)
+	case *image.RGBA64:
+		return len(img.Pix)
+	case *image.NRGBA64:
+		return len(img.Pix)
+	case *image.Gray:
+		return len(img.Pix)
+	case *image.Gray16:
+		return len(img.Pix)
+	case *image.Paletted:
+		return len(img.Pix)
+	case *image.YCbCr:
+		return len(img.Y) + len(img.Cb) + len(img.Cr)
+	}
+	b := img.Bounds()
+	return b.Dx() * b.Dy() * 4
+}
+
+// isCoverCached reports whether path has been decoded, well or not
+func isCoverCached(path string) bool {
+	coverCache.Lock()
+	defer coverCache.Unlock()
+	_, ok := coverCache.items[path]
+	return ok
+}
+
+// prefetchQueue holds the latest games to prefetch; a newer selection
+// replaces a batch still waiting
+var (
+	prefetchQueue = make(chan prefetchBatch, 1)
+	prefetchOnce  sync.Once
+)
+
+// prefetchCovers decodes the covers of the games around row i in the
+// background, so moving the selection finds them ready
+func prefetchCovers(i int) {
+	if imageProto == protoNone || coverCacheBytes <= 0 {
+		return
+	}
+	batch := prefetchBatch{screenshots: showScreenshots}
+	for d := 1; d <= coverPrefetch; d++ {
+		for _, j := range []int{i + d, i - d} {
+			if g, ok := gameAt(j); ok {
+				batch.games = append(batch.games, g)
+			}
+		}
+	}
+	if len(batch.games) == 0 {
+		return
+	}
+	prefetchOnce.Do(func() { go prefetchLoop() })
+	select {
+	case <-prefetchQueue:
+	default:
+	}
+	prefetchQueue <- batch
+}
+
+// prefetchBatch is the games whose images to decode, and whether the details
+// pane shows their screenshots rather than their covers
+type prefetchBatch struct {
+	games       []GameEntry
+	screenshots bool
+}
+
+// prefetchLoop decodes the queued games' images that aren't cached yet
+func prefetchLoop() {
+	for batch := range prefetchQueue {
+		for _, g := range batch.games {
+			if len(prefetchQueue) > 0 {
+				break // the selection moved on
+			}
+			if path := imageFor(g, batch.screenshots); path != "" && !isCoverCached(path) {
+				cachedImage(path)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"image"
+	"testing"
+)
+
+// TestCoverCacheBoundedByBytes checks the cache evicts by decoded size, so a
+// few large covers push out older ones while many small ones fit
+func TestCoverCacheBoundedByBytes(t *testing.T) {
+	old := coverCacheBytes
+	reset := func() {
+		coverCache.Lock()
+		coverCache.order, coverCache.items, coverCache.bytes = list.New(), map[string]*list.Element{}, 0
+		coverCache.Unlock()
+	}
+	t.Cleanup(func() {
+		coverCacheBytes = old
+		reset()
+	})
+	reset()
+
+	big := image.NewRGBA(image.Rect(0, 0, 512, 512)) // 1 MiB
+	coverCacheBytes = 3 << 20
+	for i := 0; i < 4; i++ {
+		storeCover(&decodedCover{path: fmt.Sprint("big", i), img: big, size: imageBytes(big)})
+	}
+	if isCoverCached("big0") {
+		t.Error("the oldest large cover outlived the byte budget")
+	}
+	for i := 1; i < 4; i++ {
+		if !isCoverCached(fmt.Sprint("big", i)) {
+			t.Errorf("big%d was evicted within the budget", i)
+		}
+	}
+
+	reset()
+	small := image.NewRGBA(image.Rect(0, 0, 64, 64)) // 16 KiB
+	for i := 0; i < 100; i++ {
+		storeCover(&decodedCover{path: fmt.Sprint("small", i), img: small, size: imageBytes(small)})
+	}
+	if !isCoverCached("small0") {
+		t.Error("100 small covers didn't fit in 3 MiB")
+	}
+}
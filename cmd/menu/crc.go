@@ -0,0 +1,227 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// crcDelay is how long a game must stay highlighted before it is hashed, so
+// scrolling past games doesn't read them all
+const crcDelay = 300 * time.Millisecond
+
+// crcResult is a hashed file's checksum, or why it couldn't be read
+type crcResult struct {
+	sum uint32
+	err error
+}
+
+// The checksums worked out so far, keyed by path and modification time, and
+// the one the details pane is waiting for
+var (
+	crcMu      sync.Mutex
+	crcs       = map[string]crcResult{}
+	crcPending = map[string]bool{}
+	crcWanted  string
+
+	dats = map[string]*DatIndex{}
+)
+
+// computeCRC32 returns the CRC32 of a ROM. Zip members use the checksum the
+// archive already records; other files are read in chunks.
+func computeCRC32(path string) (uint32, error) {
+	if archive, member, ok := splitArchivePath(path); ok {
+		r, err := zip.OpenReader(archive)
+		if err != nil {
+			return 0, err
+		}
+		defer r.Close()
+		for _, f := range r.File {
+			if f.Name == member {
+				return f.CRC32, nil
+			}
+		}
+		return 0, fmt.Errorf("%s: no %s in archive", archive, member)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	h := crc32.NewIEEE()
+	if _, err := io.Copy(h, f); err != nil {
+		return 0, err
+	}
+	return h.Sum32(), nil
+}
+
+// crcDetails is the checksum part of the details pane. A game not hashed
+// yet shows a placeholder and is hashed in the background if it stays
+// highlighted; the pane is redrawn when the result is in.
+func crcDetails(entry GameEntry, modTime time.Time) string {
+	key := entry.Path + "@" + strconv.FormatInt(modTime.UnixNano(), 10)
+	crcMu.Lock()
+	res, ok := crcs[key]
+	if !ok {
+		crcWanted = key
+		if !crcPending[key] {
+			crcPending[key] = true
+			ui.spawn(func() { hashForDetails(entry, key) })
+		}
+	}
+	dat := dats[entry.System]
+	crcMu.Unlock()
+
+	if !ok {
+		return "CRC32:    [gray]…[-]\n"
+	}
+	if res.err != nil {
+		return fmt.Sprintf("CRC32:    [red]%s[-]\n", tview.Escape(res.err.Error()))
+	}
+	text := fmt.Sprintf("CRC32:    %08X\n", res.sum)
+	if dat != nil {
+		text += dat.details(res.sum)
+	}
+	return text
+}
+
+// hashForDetails hashes entry once it has stayed highlighted for crcDelay,
+// loading its system's DAT on the way, then refreshes the details pane
+func hashForDetails(entry GameEntry, key string) {
+	time.Sleep(crcDelay)
+	crcMu.Lock()
+	wanted := crcWanted == key
+	if !wanted {
+		delete(crcPending, key)
+	}
+	crcMu.Unlock()
+	if !wanted {
+		return
+	}
+
+	sum, err := computeCRC32(entry.Path)
+	dat := loadSystemDat(entry.System)
+	crcMu.Lock()
+	crcs[key] = crcResult{sum: sum, err: err}
+	delete(crcPending, key)
+	dats[entry.System] = dat
+	crcMu.Unlock()
+
+	ui.update(func() {
+		if g, ok := highlightedGame(); ok && sameGame(g, entry) {
+			refreshDetails()
+		}
+	})
+}
+
+// loadSystemDat reads the DAT configured for system unless that was done
+// already; nil if there is none or it can't be read
+func loadSystemDat(system string) *DatIndex {
+	crcMu.Lock()
+	dat, loaded := dats[system]
+	crcMu.Unlock()
+	if loaded {
+		return dat
+	}
+	sys, ok := cfg.findSystem(system)
+	if !ok || sys.Dat == "" {
+		return nil
+	}
+	dat, err := LoadDatIndex(sys.Dat)
+	if err != nil {
+		logWarn("%s: %v", system, err)
+		return nil
+	}
+	return dat
+}
+
+// DatRom is one ROM listed in a DAT file
+type DatRom struct {
+	Game   string
+	Name   string
+	Size   int64
+	Status string
+}
+
+// DatIndex finds No-Intro/Redump DAT entries by CRC32
+type DatIndex struct {
+	roms map[uint32]DatRom
+}
+
+// datGame is a <game> (or MAME-style <machine>) element of a Logiqx DAT
+type datGame struct {
+	Name string `xml:"name,attr"`
+	Roms []struct {
+		Name   string `xml:"name,attr"`
+		Size   int64  `xml:"size,attr"`
+		CRC    string `xml:"crc,attr"`
+		Status string `xml:"status,attr"`
+	} `xml:"rom"`
+}
+
+// LoadDatIndex reads a Logiqx XML DAT, one game element at a time so big
+// DATs never sit in memory as a whole document
+func LoadDatIndex(path string) (*DatIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	idx := &DatIndex{roms: map[uint32]DatRom{}}
+	dec := xml.NewDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return idx, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || (start.Name.Local != "game" && start.Name.Local != "machine") {
+			continue
+		}
+		var g datGame
+		if err := dec.DecodeElement(&g, &start); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, r := range g.Roms {
+			crc, err := strconv.ParseUint(r.CRC, 16, 32)
+			if err != nil {
+				continue // nodump entries have no checksum
+			}
+			idx.roms[uint32(crc)] = DatRom{Game: g.Name, Name: r.Name, Size: r.Size, Status: r.Status}
+		}
+	}
+}
+
+// Lookup finds the DAT entry with checksum crc
+func (d *DatIndex) Lookup(crc uint32) (DatRom, bool) {
+	r, ok := d.roms[crc]
+	return r, ok
+}
+
+// details is the DAT part of the details pane: the verified name and
+// whether the dump is good, bad or unknown to the DAT
+func (d *DatIndex) details(crc uint32) string {
+	r, ok := d.Lookup(crc)
+	if !ok {
+		return "DAT:      [gray]unknown[-]\n"
+	}
+	status := "[green]good[-]"
+	if strings.EqualFold(r.Status, "baddump") {
+		status = "[red]bad[-]"
+	}
+	return fmt.Sprintf("DAT:      %s (%s)\n", tview.Escape(r.Game), status)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// detailsPane shows information about the highlighted game, inside
+// detailsColumn together with the cover art region
+var (
+	detailsPane   *tview.TextView
+	detailsColumn *tview.Flex
+)
+
+// regionTag matches a parenthesised No-Intro style tag such as "(USA, Europe)"
+var regionTag = regexp.MustCompile(`\(([^)]*)\)`)
+
+// knownRegions are the tag words recognised as regions
+var knownRegions = map[string]bool{
+	"USA": true, "Europe": true, "Japan": true, "World": true, "Asia": true,
+	"Australia": true, "Brazil": true, "Canada": true, "China": true, "France": true,
+	"Germany": true, "Italy": true, "Korea": true, "Spain": true, "Sweden": true,
+	"U": true, "E": true, "J": true, "UE": true, "JU": true, "JUE": true,
+}
+
+// setupDetails creates the right-hand game information column
+func setupDetails() {
+	detailsPane = tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	detailsColumn = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(detailsPane, 0, 1, false)
+	detailsColumn.SetBorder(true).SetTitle(" Details ")
+
+	if imageProto != protoNone {
+		coverBox = tview.NewBox()
+		detailsColumn.AddItem(coverBox, coverRows, 0, false)
+	}
+}
+
+// refreshDetails shows the highlighted game in the details pane
+func refreshDetails() {
+	entry, ok := highlightedGame()
+	if !ok {
+		detailsPane.Clear()
+		coverPath = ""
+		return
+	}
+	preloadMetadata(entry.System)
+	coverPath = gameImage(entry)
+	prefetchCovers(gameList.GetCurrentItem())
+	text := gameDetails(entry) + metadataDescription(entry)
+	if imageProto == protoNone || coverPath == "" {
+		text += "\n[gray]no image[-]\n"
+	}
+	detailsPane.SetText(text).ScrollToBeginning()
+}
+
+// gameDetails describes a game's file for the details pane
+func gameDetails(entry GameEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]%s[-]\n\n", tview.Escape(displayTitle(entry)))
+	fmt.Fprintf(&b, "System:   %s\n", entry.System)
+	if region := detectRegion(entry.Title); region != "" {
+		fmt.Fprintf(&b, "Region:   %s\n", region)
+	}
+	b.WriteString(arcadeDetails(entry))
+	b.WriteString(discDetails(entry))
+	b.WriteString(playDetails(entry))
+	b.WriteString(metadataDetails(entry))
+
+	if entry.Path == "" {
+		if entry.Command != "" {
+			fmt.Fprintf(&b, "Command:  %s\n", tview.Escape(entry.Command))
+		}
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "Path:     %s\n", tview.Escape(entry.Path))
+	file := entry.Path
+	if archive, _, ok := splitArchivePath(entry.Path); ok {
+		file = archive
+		fmt.Fprintf(&b, "Archive:  %s\n", tview.Escape(filepath.Base(archive)))
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		fmt.Fprintf(&b, "[red]%s[-]\n", tview.Escape(err.Error()))
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Size:     %s\n", formatSize(info.Size()))
+	fmt.Fprintf(&b, "Modified: %s\n", info.ModTime().Format("2006-01-02 15:04"))
+	b.WriteString(crcDetails(entry, info.ModTime()))
+	return b.String()
+}
+
+// detectRegion returns the region named in a title's tags, e.g. "USA"
+func detectRegion(title string) string {
+	for _, m := range regionTag.FindAllStringSubmatch(title, -1) {
+		for _, part := range strings.Split(m[1], ",") {
+			if knownRegions[strings.TrimSpace(part)] {
+				return m[1]
+			}
+		}
+	}
+	return ""
+}
+
+// formatSize renders a byte count in human units
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// unknownSystem lists files in the generic folder that match no system
+	unknownSystem = "Unknown"
+	// genericKey is the scan cache entry for the generic folder
+	genericKey = "\x00generic"
+	// headerPeek is enough of a file to reach a SNES header behind a copier
+	// header at the HiROM offset
+	headerPeek = 0x10000 + 512
+)
+
+// genericDir is the folder of mixed ROMs that are sorted into systems
+func genericDir() string {
+	return filepath.Join(gamesDir, "roms")
+}
+
+// detectSystem classifies a ROM by its header when its extension doesn't
+// say: iNES for NES, "SEGA" (or an SMD header) for Genesis and a valid
+// internal header for SNES
+func detectSystem(p string) (string, bool) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+	data := make([]byte, headerPeek)
+	n, _ := io.ReadFull(f, data)
+	return detectHeader(data[:n])
+}
+
+// detectHeader is detectSystem for the first bytes of a file
+func detectHeader(data []byte) (string, bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte("NES\x1a")):
+		return "NES", true
+	case len(data) >= 0x104 && string(data[0x100:0x104]) == "SEGA":
+		return "Genesis", true
+	case len(data) >= 10 && data[8] == 0xaa && data[9] == 0xbb:
+		// Super Magic Drive dumps start with this 512-byte header
+		return "Genesis", true
+	}
+	for _, copier := range []int{0, 512} {
+		for _, base := range []int{0x7fc0, 0xffc0} {
+			if snesHeaderAt(data, copier+base) {
+				return "SNES", true
+			}
+		}
+	}
+	return "", false
+}
+
+// snesHeaderAt reports whether a SNES internal header sits at off: its
+// checksum and complement must add up to 0xffff
+func snesHeaderAt(data []byte, off int) bool {
+	if off+0x20 > len(data) {
+		return false
+	}
+	complement := binary.LittleEndian.Uint16(data[off+0x1c:])
+	checksum := binary.LittleEndian.Uint16(data[off+0x1e:])
+	return complement^checksum == 0xffff && checksum != 0 && checksum != 0xffff
+}
+
+// systemForExtension finds the configured system that loads ext, if any
+func systemForExtension(ext string) (string, bool) {
+	for _, sys := range cfg.Systems {
+		if hasExtension(sys.Name, ext) {
+			return sys.Name, true
+		}
+	}
+	return "", false
+}
+
+// classify picks the configured system for a file by extension, then by
+// header; files matching nothing go to unknownSystem
+func classify(full string) string {
+	if system, ok := systemForExtension(filepath.Ext(full)); ok {
+		return system
+	}
+	return sniffSystem(full)
+}
+
+// sniffSystem is the configured system a file's header points to, or
+// unknownSystem. Only files whose extension no system claims are worth
+// sniffing. Detections are matched to a system by name, then by core, so a
+// renamed system still gets its ROMs.
+func sniffSystem(full string) string {
+	detected, ok := detectSystem(full)
+	if !ok {
+		return unknownSystem
+	}
+	if sys, ok := cfg.findSystem(detected); ok {
+		return sys.Name
+	}
+	for _, sys := range cfg.Systems {
+		if sys.coreName() == detected {
+			return sys.Name
+		}
+	}
+	return unknownSystem
+}
+
+// genericGames returns the games of the generic folder that belong to
+// system and pass its filters, scanning the folder once and caching the
+// result
+func genericGames(ctx context.Context, system string) ([]GameEntry, error) {
+	romCacheMu.Lock()
+	all, ok := romCache[genericKey]
+	romCacheMu.Unlock()
+	if !ok {
+		var err error
+		if all, err = scanGeneric(ctx); err != nil {
+			return nil, err
+		}
+		romCacheMu.Lock()
+		romCache[genericKey] = all
+		romCacheMu.Unlock()
+	}
+
+	sys, _ := cfg.findSystem(system)
+	var games []GameEntry
+	for _, g := range all {
+		if g.System == system && matchesFilters(filepath.Base(g.Path), sys.Include, sys.Exclude) {
+			games = append(games, g)
+		}
+	}
+	return games, nil
+}
+
+// scanGeneric walks the generic folder, sorting every file into a system.
+// Archive members are sorted by extension alone.
+func scanGeneric(ctx context.Context) ([]GameEntry, error) {
+	dir := genericDir()
+	var games []GameEntry
+	err := filepath.WalkDir(dir, func(full string, f fs.DirEntry, err error) error {
+		if err != nil {
+			if full == dir {
+				return err
+			}
+			logWarn("skipping %s: %v", full, err)
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if f.IsDir() {
+			if full != dir && strings.HasPrefix(f.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(f.Name(), ".") {
+			return nil
+		}
+
+		// Keep these apart from the system's own folders in its tree
+		entry := GameEntry{Folder: path.Join(filepath.Base(dir), relFolder(dir, full))}
+		if info, err := f.Info(); err == nil {
+			entry.ModTime = info.ModTime()
+		}
+		ext := filepath.Ext(f.Name())
+		if strings.EqualFold(ext, ".zip") {
+			inner, err := scanGenericZip(full, entry)
+			if err != nil {
+				logWarn("skipping %s: %v", full, err)
+			}
+			games = append(games, inner...)
+			return nil
+		}
+		entry.Title = strings.TrimSuffix(f.Name(), ext)
+		entry.Path = full
+		entry.System = classify(full)
+		games = append(games, entry)
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// scanGenericZip lists an archive in the generic folder like scanZip,
+// sorting each member into the system its extension belongs to
+func scanGenericZip(zipPath string, entry GameEntry) ([]GameEntry, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var games []GameEntry
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		ext := path.Ext(f.Name)
+		g := entry
+		g.Title = strings.TrimSuffix(path.Base(f.Name), ext)
+		g.Path = zipPath + "/" + f.Name
+		g.System = unknownSystem
+		if system, ok := systemForExtension(ext); ok {
+			g.System = system
+		}
+		games = append(games, g)
+	}
+	if len(games) == 1 {
+		games[0].Title = strings.TrimSuffix(filepath.Base(zipPath), filepath.Ext(zipPath))
+	}
+	return games, nil
+}
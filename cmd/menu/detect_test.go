@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestScanSniffsOnlyUnclaimedExtensions checks a misnamed ROM is listed by
+// its header under a system that runs its core, and that a file with another
+// system's extension is left to that system whatever its header says
+func TestScanSniffsOnlyUnclaimedExtensions(t *testing.T) {
+	old, oldDir := cfg, gamesDir
+	t.Cleanup(func() { cfg, gamesDir = old, oldDir })
+	gamesDir = t.TempDir()
+	cfg = &Config{Systems: []SystemConfig{
+		{Name: "Famicom", Core: "NES", Extensions: []string{".nes"}},
+		{Name: "SNES"},
+	}}
+	romCacheMu.Lock()
+	romCache = map[string][]GameEntry{}
+	romCacheMu.Unlock()
+
+	ines := append([]byte("NES\x1a"), make([]byte, 16)...)
+	dir := filepath.Join(gamesDir, "Famicom")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Misnamed.bin", "Claimed.sfc"} {
+		if err := os.WriteFile(filepath.Join(dir, name), ines, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	games, err := scanRomsContext(context.Background(), "Famicom", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 1 || games[0].Title != "Misnamed" {
+		t.Errorf("Famicom lists %v, want only Misnamed", games)
+	}
+	if got := sniffSystem(filepath.Join(dir, "Misnamed.bin")); got != "Famicom" {
+		t.Errorf("an iNES header sniffs as %s, want Famicom", got)
+	}
+	cfg.Systems = cfg.Systems[1:]
+	if got := sniffSystem(filepath.Join(dir, "Misnamed.bin")); got != unknownSystem {
+		t.Errorf("with no NES system an iNES header sniffs as %s, want %s", got, unknownSystem)
+	}
+}
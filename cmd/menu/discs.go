@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// discTag matches a disc number in a title, e.g. "(Disc 2)" or "(CD 1 of 3)"
+var discTag = regexp.MustCompile(`(?i)\s*\((?:disc|disk|cd)\s*(\d+)(?:\s*of\s*\d+)?\)`)
+
+// discChoices remembers the disc last played of each multi-disc game, keyed
+// by discSetKey and saved in discs.json
+var discChoices = map[string]string{}
+
+// discsPath is the file the current discs are saved to
+func discsPath() string {
+	return filepath.Join(configDir(), "discs.json")
+}
+
+// loadDiscChoices reads discs.json, leaving no choices if it is missing
+func loadDiscChoices() {
+	loadJSON(discsPath(), &discChoices)
+	if discChoices == nil {
+		discChoices = map[string]string{}
+	}
+}
+
+// discNumber is the disc a title says it is, if any
+func discNumber(title string) (int, bool) {
+	m := discTag.FindStringSubmatch(title)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil
+}
+
+// discSetKey identifies the set a disc belongs to within its folder
+func discSetKey(g GameEntry) string {
+	return g.System + "/" + g.Folder + "/" + strings.ToLower(discTag.ReplaceAllString(g.Title, ""))
+}
+
+// groupDiscs folds each multi-disc set into one entry, in order of first
+// appearance. The entry is titled without the disc tag, launches the disc
+// played last (or the first) and lists every disc in Discs. Lone discs are
+// left as they are.
+func groupDiscs(entries []GameEntry) []GameEntry {
+	sets := map[string][]GameEntry{}
+	for _, g := range entries {
+		if _, ok := discNumber(g.Title); ok {
+			key := discSetKey(g)
+			sets[key] = append(sets[key], g)
+		}
+	}
+
+	grouped := make([]GameEntry, 0, len(entries))
+	done := map[string]bool{}
+	for _, g := range entries {
+		if _, ok := discNumber(g.Title); !ok {
+			grouped = append(grouped, g)
+			continue
+		}
+		key := discSetKey(g)
+		discs := sets[key]
+		if len(discs) < 2 {
+			grouped = append(grouped, g)
+			continue
+		}
+		if done[key] {
+			continue
+		}
+		done[key] = true
+		sort.SliceStable(discs, func(i, j int) bool {
+			a, _ := discNumber(discs[i].Title)
+			b, _ := discNumber(discs[j].Title)
+			return a < b
+		})
+		set := currentDisc(discs)
+		set.Title = strings.TrimSpace(discTag.ReplaceAllString(set.Title, ""))
+		set.Discs = discs
+		grouped = append(grouped, set)
+	}
+	return grouped
+}
+
+// currentDisc is the disc of a set played last, or its first
+func currentDisc(discs []GameEntry) GameEntry {
+	chosen := discChoices[discSetKey(discs[0])]
+	for _, d := range discs {
+		if d.Path == chosen {
+			return d
+		}
+	}
+	return discs[0]
+}
+
+// rememberDisc makes disc the current one of its set
+func rememberDisc(disc GameEntry) {
+	discChoices[discSetKey(disc)] = disc.Path
+	if err := saveJSON(discsPath(), discChoices); err != nil {
+		reportSaveError("current discs", err)
+	}
+}
+
+// discKey offers the discs of the highlighted multi-disc game on the disc
+// key ('D'). Picking one makes it the current disc and sends the core the
+// command to load it.
+func discKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Disc.Matches(event) {
+		return event
+	}
+	game, ok := highlightedGame()
+	if !ok || len(game.Discs) < 2 {
+		return nil
+	}
+	discs := game.Discs
+	labels := make([]string, len(discs))
+	start := 0
+	for i, d := range discs {
+		n, _ := discNumber(d.Title)
+		labels[i] = fmt.Sprintf("Disc %d", n)
+		if d.Path == game.Path {
+			labels[i] += " (current)"
+			start = i
+		}
+	}
+	showPicker("Switch disc: "+displayTitle(game), labels, start, func(i int) {
+		rememberDisc(discs[i])
+		filterGames(searchBox.GetText())
+		startGame(discs[i])
+	})
+	return nil
+}
+
+// discDetails describes a multi-disc set for the details pane
+func discDetails(entry GameEntry) string {
+	if len(entry.Discs) < 2 {
+		return ""
+	}
+	for i, d := range entry.Discs {
+		if d.Path == entry.Path {
+			return fmt.Sprintf("Discs:    %d (on disc %d)\n", len(entry.Discs), i+1)
+		}
+	}
+	return fmt.Sprintf("Discs:    %d\n", len(entry.Discs))
+}
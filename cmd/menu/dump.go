@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configDump is what -dump-config prints: the configuration in effect once
+// defaults, the systems file, saved settings and flags are merged
+type configDump struct {
+	Config   string            `yaml:"config"`
+	GamesDir string            `yaml:"games_dir"`
+	MediaDir string            `yaml:"media_dir"`
+	StateDir string            `yaml:"state_dir"`
+	Lang     string            `yaml:"lang"`
+	Flags    map[string]string `yaml:"flags,omitempty"`
+	Settings Settings          `yaml:"settings"`
+	Keys     map[string]string `yaml:"keys"`
+	Systems  []SystemConfig    `yaml:"systems"`
+	Titles   TitleRules        `yaml:"titles,omitempty"`
+	Hooks    LaunchHooks       `yaml:"hooks,omitempty"`
+}
+
+// Dump writes the effective configuration as YAML: resolved paths, the
+// flags given, the settings and key map in effect, and each system with
+// its ROM folder, core and extensions filled in
+func (c *Config) Dump(w io.Writer) error {
+	d := configDump{
+		Config:   absPath(configPath),
+		GamesDir: absPath(gamesDir),
+		MediaDir: absPath(mediaDir()),
+		StateDir: absPath(configDir()),
+		Lang:     langCode,
+		Flags:    map[string]string{},
+		Settings: currentSettings(),
+		Keys:     map[string]string{},
+		Titles:   c.Titles,
+		Hooks:    c.Hooks,
+	}
+	flag.Visit(func(f *flag.Flag) {
+		d.Flags[f.Name] = f.Value.String()
+	})
+	for name, b := range keys.actions() {
+		d.Keys[name] = b.String()
+	}
+	for _, sys := range c.Systems {
+		sys.Dir = absPath(sys.romDir())
+		sys.Core = sys.coreName()
+		sys.Extensions = extensionsFor(sys.Name)
+		d.Systems = append(d.Systems, sys)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(d); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// absPath makes p absolute for display, leaving it as is if that fails
+func absPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		return abs
+	}
+	return p
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// dupHashLimit caps how much of each end of a file is hashed. CD images of
+// one series can share their first sectors, so the tail is read as well;
+// with the size that tells them apart without reading whole images.
+const dupHashLimit = 16 << 20
+
+// DupGroup is one ROM found under more than one system
+type DupGroup struct {
+	Hash  string
+	Size  int64
+	Games []GameEntry
+}
+
+// findDuplicates scans every system and returns the files whose contents
+// match a file of another system. Only files sharing a size are hashed, and
+// ROMs inside archives are skipped.
+func findDuplicates() []DupGroup {
+	bySize := map[int64][]GameEntry{}
+	for _, sys := range cfg.Systems {
+		roms, err := cachedRoms(sys.Name)
+		if err != nil {
+			logWarn("duplicates: %s: %v", sys.Name, err)
+			continue
+		}
+		for _, g := range roms {
+			if _, _, inArchive := splitArchivePath(g.Path); inArchive {
+				continue
+			}
+			if info, err := os.Stat(g.Path); err == nil && info.Mode().IsRegular() {
+				bySize[info.Size()] = append(bySize[info.Size()], g)
+			}
+		}
+	}
+
+	var groups []DupGroup
+	for size, games := range bySize {
+		if len(games) < 2 || !severalSystems(games) {
+			continue
+		}
+		byHash := map[string][]GameEntry{}
+		for _, g := range games {
+			sum, err := hashFile(g.Path, size, dupHashLimit)
+			if err != nil {
+				logWarn("duplicates: %v", err)
+				continue
+			}
+			byHash[sum] = append(byHash[sum], g)
+		}
+		for sum, same := range byHash {
+			if severalSystems(same) {
+				groups = append(groups, DupGroup{Hash: sum, Size: size, Games: same})
+			}
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Games[0].Path < groups[j].Games[0].Path
+	})
+	return groups
+}
+
+// severalSystems reports whether games come from more than one system
+func severalSystems(games []GameEntry) bool {
+	for _, g := range games[1:] {
+		if g.System != games[0].System {
+			return true
+		}
+	}
+	return false
+}
+
+// hashFile returns the SHA-1 of the first and last limit bytes of path, a
+// file of size bytes, or of all of it when that is no more. It is read in
+// chunks so memory use doesn't grow with the file.
+func hashFile(path string, size, limit int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha1.New()
+	if _, err := io.Copy(h, io.LimitReader(f, limit)); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	if size > limit {
+		tail := size - limit
+		if tail < limit {
+			tail = limit
+		}
+		if _, err := io.Copy(h, io.NewSectionReader(f, tail, size-tail)); err != nil {
+			return "", fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// duplicateReport formats the groups for reading, one path per line
+func duplicateReport(groups []DupGroup) string {
+	if len(groups) == 0 {
+		return "No ROM is filed under more than one system."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d ROMs are filed under more than one system:\n", len(groups))
+	for _, g := range groups {
+		fmt.Fprintf(&b, "\n%s (%d bytes, %s)\n", tview.Escape(g.Games[0].Title), g.Size, g.Hash[:8])
+		for _, game := range g.Games {
+			fmt.Fprintf(&b, "  [yellow]%s[-]  %s\n", tview.Escape(game.System), tview.Escape(game.Path))
+		}
+	}
+	return b.String()
+}
+
+// showDuplicates looks for duplicates in the background and then shows the
+// report in a scrollable window
+func showDuplicates() {
+	modal := tview.NewModal().SetText("Looking for duplicate ROMs...")
+	nav.push(modal)
+	ui.spawn(func() {
+		report := duplicateReport(findDuplicates())
+		ui.update(func() {
+			nav.close(modal)
+			view := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetText(report)
+			view.SetDoneFunc(func(tcell.Key) { nav.pop() })
+			view.SetBorder(true).SetTitle(" Duplicate ROMs (Esc to close) ")
+			nav.push(view)
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestHashFileReadsTail checks files that only differ past the hashed head,
+// like CD images sharing boot sectors, hash differently
+func TestHashFileReadsTail(t *testing.T) {
+	const limit, size = 1 << 10, 8 << 10
+	dir := t.TempDir()
+	hash := func(name string, last byte) string {
+		data := make([]byte, size)
+		data[size-1] = last
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		sum, err := hashFile(path, size, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sum
+	}
+	a, b, c := hash("a.bin", 1), hash("b.bin", 2), hash("c.bin", 1)
+	if a == b {
+		t.Error("files differing at the end hash the same")
+	}
+	if a != c {
+		t.Error("identical files hash differently")
+	}
+}
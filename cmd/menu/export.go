@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rivo/tview"
+)
+
+// exportedState is the portable file holding favorites and recent games
+type exportedState struct {
+	Favorites []GameEntry `json:"favorites"`
+	Recent    []GameEntry `json:"recent"`
+}
+
+// exportPath is where the menu's export and import actions look
+func exportPath() string {
+	return filepath.Join(configDir(), "export.json")
+}
+
+// exportState writes the favorites and recent lists to path
+func exportState(path string) error {
+	return saveJSON(path, exportedState{Favorites: favoriteGames, Recent: recentGames})
+}
+
+// importState reads lists written by exportState. With replace they take
+// the place of the local lists; otherwise they are added after local entries,
+// skipping duplicates. Entries for games missing here are kept.
+func importState(path string, replace bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var st exportedState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if replace {
+		favoriteGames, recentGames = st.Favorites, st.Recent
+	} else {
+		favoriteGames = mergeGames(favoriteGames, st.Favorites, 0)
+		recentGames = mergeGames(recentGames, st.Recent, maxRecent)
+	}
+	if len(recentGames) > maxRecent {
+		recentGames = recentGames[:maxRecent]
+	}
+
+	if err := saveJSON(favoritesPath(), favoriteGames); err != nil {
+		return err
+	}
+	if err := saveJSON(recentPath(), recentGames); err != nil {
+		return err
+	}
+	return nil
+}
+
+// mergeGames appends the games from more that aren't in games, up to limit
+// entries in all (no limit when 0)
+func mergeGames(games, more []GameEntry, limit int) []GameEntry {
+	merged := append([]GameEntry(nil), games...)
+	for _, g := range more {
+		if limit > 0 && len(merged) >= limit {
+			break
+		}
+		dup := false
+		for _, have := range merged {
+			if sameGame(have, g) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			merged = append(merged, g)
+		}
+	}
+	return merged
+}
+
+// gameAvailable reports whether a game's ROM exists on this install; games
+// launched by command alone always are
+func gameAvailable(game GameEntry) bool {
+	path := game.Path
+	if path == "" {
+		return true
+	}
+	if archive, _, ok := splitArchivePath(path); ok {
+		path = archive
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// exportFromMenu writes export.json and says where it went
+func exportFromMenu() {
+	path := exportPath()
+	if err := exportState(path); err != nil {
+		showError(app, T("error.export"), err)
+		return
+	}
+	logInfo("exported favorites and recent games to %s", path)
+	showMessage(app, fmt.Sprintf(T("msg.lists_exported"),
+		len(favoriteGames), len(recentGames), path))
+}
+
+// importFromMenu asks whether export.json should be merged into the local
+// lists or replace them, then imports it
+func importFromMenu() {
+	path := exportPath()
+	if _, err := os.Stat(path); err != nil {
+		showError(app, T("error.nothing_import"), err)
+		return
+	}
+	modal := tview.NewModal().
+		SetText("Import favorites and recent games from\n" + path + "?\n\nMerge keeps your lists and adds the imported games; Replace swaps them.").
+		AddButtons([]string{"Merge", "Replace", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			nav.pop()
+			if label != "Merge" && label != "Replace" {
+				return
+			}
+			if err := importState(path, label == "Replace"); err != nil {
+				showError(app, T("error.import"), err)
+				return
+			}
+			setSystemCount(recentSystem, len(recentGames))
+			setSystemCount(favoritesSystem, len(favoriteGames))
+			if currentSystem == recentSystem || currentSystem == favoritesSystem {
+				loadGames(app, currentSystem)
+			} else {
+				filterGames(searchBox.GetText())
+			}
+		})
+	nav.push(modal)
+}
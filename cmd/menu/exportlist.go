@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Formats exportVisibleList writes
+const (
+	listFormatText = "txt"
+	listFormatCSV  = "csv"
+)
+
+// listExportPath is where the shown list of system is exported to in format
+func listExportPath(system, format string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, system)
+	return filepath.Join(configDir(), "list-"+name+"."+format)
+}
+
+// exportVisibleList writes the game list as it is shown, in its order and
+// with any search or filter applied, to path: one title per line for
+// listFormatText, or title, system, path and size in bytes for
+// listFormatCSV. Rows hidden only by max_items are included; folders and
+// separators are not.
+func exportVisibleList(path, format string) error {
+	var games []gameRow
+	for _, r := range allRows() {
+		if r.isGame() {
+			games = append(games, r)
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case listFormatText:
+		for _, r := range games {
+			buf.WriteString(rowTitle(r) + "\n")
+		}
+	case listFormatCSV:
+		w := csv.NewWriter(&buf)
+		w.Write([]string{"title", "system", "path", "size"})
+		for _, r := range games {
+			size := ""
+			if n, ok := gameSize(r.game); ok {
+				size = strconv.FormatInt(n, 10)
+			}
+			w.Write([]string{rowTitle(r), r.game.System, r.game.Path, size})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown list format %q", format)
+	}
+	return writeAtomic(path, buf.Bytes())
+}
+
+// gameSize is the size of a game's file, or of the archive holding it
+func gameSize(game GameEntry) (int64, bool) {
+	file := game.Path
+	if file == "" {
+		return 0, false
+	}
+	if archive, _, ok := splitArchivePath(file); ok {
+		file = archive
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return 0, false
+	}
+	return info.Size(), true
+}
+
+// exportListKey asks which format to export the shown list in on the
+// export key ('E'), then says where it went
+func exportListKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Export.Matches(event) {
+		return event
+	}
+	if countGames(allRows()) == 0 {
+		showMessage(app, T("msg.nothing_to_export"))
+		return nil
+	}
+	system := currentSystem
+	modal := tview.NewModal().
+		SetText(T("msg.export_list")).
+		AddButtons([]string{"Text", "CSV", T("button.cancel")}).
+		SetDoneFunc(func(i int, _ string) {
+			nav.pop()
+			var format string
+			switch i {
+			case 0:
+				format = listFormatText
+			case 1:
+				format = listFormatCSV
+			default:
+				return
+			}
+			path := listExportPath(system, format)
+			if err := exportVisibleList(path, format); err != nil {
+				showError(app, "Could not export list", err)
+				return
+			}
+			logInfo("exported %s list to %s", system, path)
+			showMessageFor(app, fmt.Sprintf(T("msg.list_exported"), countGames(allRows()), path), 0)
+		})
+	nav.push(modal)
+	return nil
+}
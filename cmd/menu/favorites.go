@@ -0,0 +1,74 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const (
+	favoritesSystem = "Favorites"
+	favoriteMarker  = "★"
+)
+
+// favoriteGames are the starred games of every system
+var favoriteGames []GameEntry
+
+// favoritesPath is the file favorites are saved to
+func favoritesPath() string {
+	return filepath.Join(configDir(), "favorites.json")
+}
+
+// loadFavorites reads the saved favorites, if there are any
+func loadFavorites() {
+	loadJSON(favoritesPath(), &favoriteGames)
+}
+
+// isFavorite reports whether game has been starred
+func isFavorite(game GameEntry) bool {
+	for _, g := range favoriteGames {
+		if sameGame(g, game) {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleFavorite stars or unstars game and saves the list
+func toggleFavorite(game GameEntry) {
+	if isFavorite(game) {
+		games := favoriteGames[:0]
+		for _, g := range favoriteGames {
+			if !sameGame(g, game) {
+				games = append(games, g)
+			}
+		}
+		favoriteGames = games
+	} else {
+		favoriteGames = append(favoriteGames, game)
+	}
+
+	if err := saveJSON(favoritesPath(), favoriteGames); err != nil {
+		reportSaveError("favorites", err)
+	}
+	setSystemCount(favoritesSystem, len(favoriteGames))
+}
+
+// favoriteKey toggles the highlighted game on the favorite key ('f')
+func favoriteKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Favorite.Matches(event) {
+		return event
+	}
+	i := gameList.GetCurrentItem()
+	game, ok := gameAt(i)
+	if !ok {
+		return nil
+	}
+
+	toggleFavorite(game)
+	title, _ := gameList.GetItemText(i)
+	indent := strings.Repeat("  ", ui.shownRows[i].depth)
+	gameList.SetItemText(i, title, indent+rowText(ui.shownRows[i]))
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import "github.com/gdamore/tcell/v2"
+
+// pane identifies one of the focusable lists
+type pane int
+
+const (
+	systemPane pane = iota
+	gamePane
+)
+
+// activePane is the list that has, or last had, keyboard focus
+var activePane = systemPane
+
+// trackFocus keeps activePane in step however focus arrives (keys or mouse)
+func trackFocus() {
+	systemList.SetFocusFunc(func() { paneFocused(systemPane) })
+	gameList.SetFocusFunc(func() { paneFocused(gamePane) })
+	searchBox.SetFocusFunc(func() { paneFocused(gamePane) })
+}
+
+// paneFocused records the active pane and refreshes the hints for it
+func paneFocused(p pane) {
+	activePane = p
+	if p == systemPane && systemsHidden {
+		// Going back to the systems brings their pane back
+		systemsHidden, sidebarLeft = false, 0
+		relayout()
+	}
+	refreshStatusBar()
+	paneChanged()
+}
+
+// focusPane moves keyboard focus to p
+func focusPane(p pane) {
+	if p == gamePane {
+		app.SetFocus(gameList)
+	} else {
+		app.SetFocus(systemList)
+	}
+}
+
+// focusKeys switches panes with Tab/Shift-Tab, with left/right at list edges
+// and with Esc from the games
+func focusKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyTab, tcell.KeyBacktab:
+		if activePane == systemPane {
+			focusPane(gamePane)
+		} else {
+			focusPane(systemPane)
+		}
+		return nil
+	case tcell.KeyRight:
+		if activePane == systemPane {
+			focusPane(gamePane)
+			return nil
+		}
+	case tcell.KeyEscape:
+		// Back out of the games, e.g. with the controller's back button
+		if activePane == gamePane {
+			focusPane(systemPane)
+			return nil
+		}
+	case tcell.KeyLeft:
+		if activePane == gamePane {
+			// Only leave once the game list is scrolled fully left
+			if _, horizontal := gameList.GetOffset(); horizontal == 0 {
+				focusPane(systemPane)
+				return nil
+			}
+		}
+	}
+	return event
+}
+
+// listFocused reports whether one of the lists, rather than the search box
+// or a modal, has keyboard focus
+func listFocused() bool {
+	focus := app.GetFocus()
+	return focus == systemList || focus == gameList
+}
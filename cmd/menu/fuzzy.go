@@ -0,0 +1,60 @@
+package main
+
+import "unicode"
+
+// Scoring weights for fuzzyScore
+const (
+	fuzzyMatch       = 1
+	fuzzyConsecutive = 5
+	fuzzyWordStart   = 8
+	fuzzyFirstChar   = 10
+	fuzzyGapPenalty  = 1
+)
+
+// fuzzyScore rates how well query matches title as a subsequence, ignoring
+// case, accents, spaces and punctuation in the query. It returns -1 when some query
+// character can't be matched in order; higher scores are better matches,
+// favouring word starts ("smb" in "Super Mario Bros.") and runs.
+func fuzzyScore(query, title string) int {
+	var q []rune
+	for _, r := range normalizeTitle(query) {
+		if isWordChar(r) {
+			q = append(q, r)
+		}
+	}
+	t := []rune(normalizeTitle(title))
+
+	score, qi, last := 0, 0, -1
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+
+		score += fuzzyMatch
+		switch {
+		case ti == 0:
+			score += fuzzyFirstChar
+		case !isWordChar(t[ti-1]):
+			score += fuzzyWordStart
+		}
+		if last >= 0 {
+			if ti == last+1 {
+				score += fuzzyConsecutive
+			} else {
+				score -= fuzzyGapPenalty
+			}
+		}
+		last = ti
+		qi++
+	}
+
+	if qi < len(q) {
+		return -1
+	}
+	return score
+}
+
+// isWordChar reports whether r is part of a word rather than a separator
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
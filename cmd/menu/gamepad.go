@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// gamepadDevice is the joystick read for controller navigation
+var gamepadDevice = "/dev/input/js0"
+
+// Linux joystick event types (linux/joystick.h)
+const (
+	jsEventButton = 0x01
+	jsEventAxis   = 0x02
+	jsEventInit   = 0x80
+)
+
+// Controller mapping and feel
+const (
+	gamepadLaunch    = 0 // button that presses Enter
+	gamepadBack      = 1 // button that presses Esc
+	gamepadDeadzone  = 16384
+	gamepadRepeatIn  = 400 * time.Millisecond
+	gamepadRepeatGap = 120 * time.Millisecond
+)
+
+// startGamepad turns joystick input into key events until ctx ends: the
+// d-pad or stick moves, one button launches and another goes back. It does
+// nothing if there is no joystick. While a core is running the pad belongs
+// to the game, so its input is dropped rather than moving the menu behind it.
+func startGamepad(ctx context.Context, app *tview.Application) {
+	f, err := os.Open(gamepadDevice)
+	if err != nil {
+		logInfo("no gamepad: %v", err)
+		return
+	}
+	go func() {
+		<-ctx.Done()
+		f.Close()
+	}()
+	logInfo("reading gamepad %s", gamepadDevice)
+
+	send := func(key tcell.Key) {
+		if atomic.LoadInt32(&coreRunning) != 0 {
+			return
+		}
+		app.QueueEvent(tcell.NewEventKey(key, 0, tcell.ModNone))
+	}
+	held := newKeyRepeater(send)
+	defer held.set(tcell.KeyNUL)
+
+	var event [8]byte
+	axes := map[uint8]tcell.Key{}
+	for {
+		if _, err := io.ReadFull(f, event[:]); err != nil {
+			if ctx.Err() == nil {
+				logWarn("gamepad: %v", err)
+			}
+			return
+		}
+		value := int16(binary.LittleEndian.Uint16(event[4:]))
+		kind, number := event[6], event[7]
+		if kind&jsEventInit != 0 {
+			continue // initial state, not a press
+		}
+
+		switch kind {
+		case jsEventButton:
+			if value != 1 {
+				continue
+			}
+			switch number {
+			case gamepadLaunch:
+				send(tcell.KeyEnter)
+			case gamepadBack:
+				send(tcell.KeyEscape)
+			}
+		case jsEventAxis:
+			prev, key := axes[number], axisKey(number, value)
+			axes[number] = key
+			if key != tcell.KeyNUL {
+				held.set(key)
+			} else if prev != tcell.KeyNUL {
+				// Only the axis that was pushed releases the hold, so a
+				// twitch on another axis doesn't stop the scrolling
+				held.set(tcell.KeyNUL)
+			}
+		}
+	}
+}
+
+// axisKey maps an axis position to an arrow key, or KeyNUL when centred.
+// Even axes are horizontal and odd ones vertical, which holds for sticks
+// and for d-pads reported as a hat.
+func axisKey(number uint8, value int16) tcell.Key {
+	switch {
+	case value > -gamepadDeadzone && value < gamepadDeadzone:
+		return tcell.KeyNUL
+	case number%2 == 0 && value < 0:
+		return tcell.KeyLeft
+	case number%2 == 0:
+		return tcell.KeyRight
+	case value < 0:
+		return tcell.KeyUp
+	default:
+		return tcell.KeyDown
+	}
+}
+
+// keyRepeater presses a held direction once and then repeats it, like a
+// keyboard does
+type keyRepeater struct {
+	mu   sync.Mutex
+	key  tcell.Key
+	stop chan struct{}
+	send func(tcell.Key)
+}
+
+// newKeyRepeater presses keys with send
+func newKeyRepeater(send func(tcell.Key)) *keyRepeater {
+	return &keyRepeater{send: send}
+}
+
+// set changes the held key; KeyNUL releases it
+func (r *keyRepeater) set(key tcell.Key) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key == r.key {
+		return
+	}
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+	r.key = key
+	if key == tcell.KeyNUL {
+		return
+	}
+
+	r.send(key)
+	stop := make(chan struct{})
+	r.stop = stop
+	go func() {
+		wait := time.NewTimer(gamepadRepeatIn)
+		defer wait.Stop()
+		select {
+		case <-stop:
+			return
+		case <-wait.C:
+		}
+		ticker := time.NewTicker(gamepadRepeatGap)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				r.send(key)
+			}
+		}
+	}()
+}
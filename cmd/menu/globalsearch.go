@@ -0,0 +1,48 @@
+package main
+
+// allSystemsSearch is the pseudo-system listing every system's games for
+// the global search
+const allSystemsSearch = "All Systems"
+
+// searchAll matches query against the cached games of every listed system.
+// Systems not scanned yet are left out until warming reaches them.
+func searchAll(query string) []GameEntry {
+	var all []GameEntry
+	for _, sys := range listedSystems() {
+		if !provider.Cached(sys.Name) {
+			continue
+		}
+		games, _ := provider.Games(sys.Name)
+		all = append(all, games...)
+	}
+	return matchGames(all, query)
+}
+
+// startGlobalSearch lists the games of every system and focuses the search
+// box. Systems still unscanned are scanned in the background and added to
+// the results as they arrive.
+func startGlobalSearch() {
+	showGames(allSystemsSearch, searchAll(""), nil)
+	app.SetFocus(searchBox)
+	if allCached() {
+		return
+	}
+
+	ui.spawn(func() {
+		for _, sys := range listedSystems() {
+			if provider.Cached(sys.Name) {
+				continue
+			}
+			if _, err := provider.Games(sys.Name); err != nil {
+				logWarn("%s: %v", sys.Name, err)
+			}
+			ui.update(func() {
+				if currentSystem == allSystemsSearch {
+					ui.gameCache = searchAll("")
+					sortGames(ui.gameCache, sortMode)
+					filterGames(searchBox.GetText())
+				}
+			})
+		}
+	})
+}
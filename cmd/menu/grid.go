@@ -0,0 +1,231 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Game list view modes, as saved in settings.game_view
+const (
+	gameViewList = "list"
+	gameViewGrid = "grid"
+)
+
+// gridCellWidth is the narrowest a grid column gets
+const gridCellWidth = 24
+
+// gridView shows the games in columns instead of one per line
+var gridView bool
+
+// gridGames is the grid drawn in place of the game list in grid mode
+var gridGames *GridGameView
+
+// GridGameView draws the game list's items in columns, left to right and
+// then down, one line each. The list stays the model and keeps keyboard
+// focus, so highlighting, launching and every list key work unchanged; the
+// grid draws it, passes focus and keys through to it and maps the arrow
+// keys and clicks onto it.
+type GridGameView struct {
+	*tview.Box
+	list *tview.List
+
+	// offset is the first grid row shown; cols and rows are the geometry
+	// of the last draw
+	offset     int
+	cols, rows int
+}
+
+// NewGridGameView makes a grid over list
+func NewGridGameView(list *tview.List) *GridGameView {
+	return &GridGameView{Box: tview.NewBox(), list: list, cols: 1, rows: 1}
+}
+
+// Draw lays the list's items out in columns, scrolled so the current one is
+// in view
+func (g *GridGameView) Draw(screen tcell.Screen) {
+	g.Box.DrawForSubclass(screen, g)
+	x, y, width, height := g.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	g.cols, g.rows = width/gridCellWidth, height
+	if g.cols < 1 {
+		g.cols = 1
+	}
+	cellWidth := width / g.cols
+
+	current := g.list.GetCurrentItem()
+	if row := current / g.cols; row < g.offset {
+		g.offset = row
+	} else if row >= g.offset+g.rows {
+		g.offset = row - g.rows + 1
+	}
+
+	n := g.list.GetItemCount()
+	for r := 0; r < g.rows; r++ {
+		for c := 0; c < g.cols; c++ {
+			i := (g.offset+r)*g.cols + c
+			if i >= n {
+				return
+			}
+			main, _ := g.list.GetItemText(i)
+			cx, cy := x+c*cellWidth, y+r
+			tview.Print(screen, main, cx, cy, cellWidth-1, tview.AlignLeft, tview.Styles.PrimaryTextColor)
+			if i == current {
+				highlightCell(screen, cx, cy, cellWidth-1)
+			}
+		}
+	}
+}
+
+// highlightCell redraws width cells from x in the selection style, keeping
+// their characters
+func highlightCell(screen tcell.Screen, x, y, width int) {
+	style := selectedStyle()
+	for cx := x; cx < x+width; cx++ {
+		ch, comb, _, _ := screen.GetContent(cx, y)
+		screen.SetContent(cx, y, ch, comb, style)
+	}
+}
+
+// selectedStyle is the highlight the lists use, as set up by styleList
+func selectedStyle() tcell.Style {
+	switch {
+	case monochrome:
+		return tcell.StyleDefault.Reverse(true)
+	case selectionColor != tcell.ColorDefault:
+		return tcell.StyleDefault.Foreground(tview.Styles.PrimaryTextColor).Background(selectionColor)
+	}
+	return tcell.StyleDefault.Foreground(tview.Styles.PrimitiveBackgroundColor).Background(tview.Styles.PrimaryTextColor)
+}
+
+// Focus passes focus on to the list, so it keeps taking the keys
+func (g *GridGameView) Focus(delegate func(p tview.Primitive)) {
+	delegate(g.list)
+}
+
+// HasFocus reports whether the list has focus. The grid stands in for the
+// list in the layout, so the layout routes the list's keys through it.
+func (g *GridGameView) HasFocus() bool {
+	return g.list.HasFocus()
+}
+
+// InputHandler hands keys to the list, whose input capture moves the
+// selection in two dimensions while the grid is shown
+func (g *GridGameView) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return g.list.InputHandler()
+}
+
+// indexAt maps a screen position to an item, or -1
+func (g *GridGameView) indexAt(x, y int) int {
+	rx, ry, width, height := g.GetInnerRect()
+	if x < rx || x >= rx+width || y < ry || y >= ry+height {
+		return -1
+	}
+	col := (x - rx) / (width / g.cols)
+	if col >= g.cols {
+		return -1
+	}
+	i := (g.offset+y-ry)*g.cols + col
+	if i >= g.list.GetItemCount() {
+		return -1
+	}
+	return i
+}
+
+// MouseHandler highlights the clicked game, launching it on a double click
+// just like the list, and scrolls by a row on the wheel
+func (g *GridGameView) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return g.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		if !g.InRect(event.Position()) {
+			return false, nil
+		}
+		switch action {
+		case tview.MouseLeftClick, tview.MouseLeftDoubleClick:
+			if i := g.indexAt(event.Position()); i >= 0 {
+				clickGame(i)
+			}
+			return true, nil
+		case tview.MouseScrollUp:
+			moveGridSelection(-g.cols)
+			return true, nil
+		case tview.MouseScrollDown:
+			moveGridSelection(g.cols)
+			return true, nil
+		}
+		return false, nil
+	})
+}
+
+// gridKey moves the selection in two dimensions while the grid is shown.
+// Left in the first column is passed on, so it still leaves for the systems.
+func gridKey(event *tcell.EventKey) *tcell.EventKey {
+	if !gridView {
+		return event
+	}
+	g := gridGames
+	switch event.Key() {
+	case tcell.KeyLeft:
+		if gameList.GetCurrentItem()%g.cols == 0 {
+			return event
+		}
+		moveGridSelection(-1)
+	case tcell.KeyRight:
+		moveGridSelection(1)
+	case tcell.KeyUp:
+		moveGridSelection(-g.cols)
+	case tcell.KeyDown:
+		moveGridSelection(g.cols)
+	case tcell.KeyPgUp:
+		moveGridSelection(-g.cols * g.rows)
+	case tcell.KeyPgDn:
+		moveGridSelection(g.cols * g.rows)
+	default:
+		return event
+	}
+	return nil
+}
+
+// moveGridSelection moves the highlight by delta items, stopping at either
+// end of the rows
+func moveGridSelection(delta int) {
+	i := gameList.GetCurrentItem() + delta
+	if last := len(ui.shownRows) - 1; i > last {
+		i = last
+	}
+	if i < 0 {
+		i = 0
+	}
+	pagedGames.Select(i)
+}
+
+// gameListView is whichever of the list and the grid currently shows games
+func gameListView() tview.Primitive {
+	if gridView {
+		return gridGames
+	}
+	return gameList
+}
+
+// viewKey toggles between the list and the grid on the view key ('v'),
+// saving the choice
+func viewKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.View.Matches(event) {
+		return event
+	}
+	gridView = !gridView
+	stopMarquee()
+	relayout()
+	gameHighlighted()
+
+	saveSetting(func(s *Settings) { s.GameView = gameViewName() })
+	return nil
+}
+
+// gameViewName is the setting value for the current view
+func gameViewName() string {
+	if gridView {
+		return gameViewGrid
+	}
+	return gameViewList
+}
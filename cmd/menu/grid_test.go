@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestGridViewKeepsKeys(t *testing.T) {
+	t.Cleanup(func() { gridView = false })
+	screen := startTestUI(t, "NES/Contra.nes", "NES/Gradius.nes", "NES/Metroid.nes", "NES/Zelda.nes")
+	openSystemForTest(t, "NES")
+	screen.InjectKey(tcell.KeyTab, 0, tcell.ModNone)
+	waitFor(t, "the games after Tab", func() bool { return activePane == gamePane })
+
+	current := func(want int) func() bool {
+		return func() bool { return gameList.GetCurrentItem() == want }
+	}
+	screen.InjectKey(tcell.KeyRune, 'v', tcell.ModNone)
+	waitFor(t, "the grid", func() bool { return gridView })
+	screen.InjectKey(tcell.KeyRight, 0, tcell.ModNone)
+	waitFor(t, "Right to move along the grid", current(1))
+
+	screen.InjectKey(tcell.KeyRune, 'v', tcell.ModNone)
+	waitFor(t, "the list again", func() bool { return !gridView })
+	screen.InjectKey(tcell.KeyDown, 0, tcell.ModNone)
+	waitFor(t, "Down to move down the list", current(2))
+}
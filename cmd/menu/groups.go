@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+// otherCategory collects systems that don't name a category
+const otherCategory = "Other"
+
+// groupSystems lists systems under category headers instead of in config
+// order
+var groupSystems bool
+
+// lastSystemRow is the system list row highlighted before the current one,
+// so header rows can be skipped in the direction of travel
+var lastSystemRow int
+
+// buildGroupedSystemList adds the systems under a header per category, in
+// the order categories first appear in the config, with uncategorised
+// systems last. Hotkeys stay on their systems whichever group they land in.
+func buildGroupedSystemList(systems []SystemConfig) {
+	var categories []string
+	groups := map[string][]SystemConfig{}
+	for _, sys := range systems {
+		category := sys.Category
+		if category == "" {
+			category = otherCategory
+		}
+		if _, ok := groups[category]; !ok && category != otherCategory {
+			categories = append(categories, category)
+		}
+		groups[category] = append(groups[category], sys)
+	}
+	if _, ok := groups[otherCategory]; ok {
+		categories = append(categories, otherCategory)
+	}
+
+	for _, category := range categories {
+		addHeaderItem(category)
+		for _, sys := range groups[category] {
+			addSystemRows(sys)
+		}
+	}
+}
+
+// addHeaderItem appends a category header, which holds no system
+func addHeaderItem(category string) {
+	systemOrder = append(systemOrder, "")
+	systemList.AddItem(fmt.Sprintf("[::b]── %s ──", tview.Escape(category)), "", 0, nil)
+}
+
+// isHeaderRow reports whether row i of the system list is a category header
+func isHeaderRow(i int) bool {
+	return i >= 0 && i < len(systemOrder) && systemOrder[i] == ""
+}
+
+// skipHeaderRow moves the system selection off a header row
+func skipHeaderRow(i int) {
+	skipRow(systemList, i, &lastSystemRow, isHeaderRow)
+}
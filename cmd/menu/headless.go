@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// runList prints a system's games to w without starting the UI and returns
+// the process exit code
+func runList(w io.Writer, system string, asJSON bool) int {
+	games, err := provider.Games(system)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", system, err)
+		return 1
+	}
+	if !keepsOrder(system) {
+		sortGames(games, SortNameAsc)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if games == nil {
+			games = []GameEntry{}
+		}
+		if err := enc.Encode(games); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	for _, g := range games {
+		fmt.Fprintln(w, g.Title)
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// actionHelp describes the rebindable actions, in the order the help lists
+// them; names match KeyMap.actions
+var actionHelp = []struct{ name, help string }{
+	{"launch", "launch the highlighted game"},
+	{"favorite", "star or unstar the game"},
+	{"search", "search this list"},
+	{"search_all", "search every system"},
+	{"sort", "change the sort order"},
+	{"refresh", "rescan the system"},
+	{"random", "launch a random game"},
+	{"preview", "preview the launch command"},
+	{"paths", "show file paths instead of titles"},
+	{"view", "switch between list and grid"},
+	{"disc", "switch disc of a multi-disc game"},
+	{"remove", "remove the game from the recent list"},
+	{"clear", "clear the recent list"},
+	{"copy", "copy the game's path to the clipboard"},
+	{"export", "export the list as shown to a text or CSV file"},
+	{"systems", "hide or bring back the system pane"},
+	{"queue", "add the game to the launch queue"},
+	{"queue_list", "show, reorder and run the launch queue"},
+	{"history", "show the launch history"},
+	{"help", "show this help"},
+	{"quit", "quit"},
+}
+
+// helpKey opens the help on the help key ('?') from either list
+func helpKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Help.Matches(event) || !listFocused() {
+		return event
+	}
+	showHelp(app)
+	return nil
+}
+
+// showHelp lists every key binding in effect, remapped ones included, and
+// closes on Esc or the help key
+func showHelp(app *tview.Application) {
+	table := tview.NewTable().SetSelectable(true, false)
+	row := 0
+	add := func(key, help string) {
+		table.SetCell(row, 0, tview.NewTableCell(tview.Escape(key)).SetTextColor(tview.Styles.SecondaryTextColor))
+		table.SetCell(row, 1, tview.NewTableCell(help).SetExpansion(1))
+		row++
+	}
+
+	actions := keys.actions()
+	described := map[string]bool{}
+	for _, a := range actionHelp {
+		if b, ok := actions[a.name]; ok {
+			add(b.String(), a.help)
+			described[a.name] = true
+		}
+	}
+	// Actions added without a description still show up
+	var rest []string
+	for name := range actions {
+		if !described[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		add(actions[name].String(), name)
+	}
+
+	add("Tab", "switch between systems and games")
+	add("Esc", "go back")
+	if letterKeys == letterKeysFilter {
+		add("a-z", "show only games starting with the letter")
+	} else {
+		add("a-z", "jump to titles starting with the letters typed")
+	}
+	if devMode {
+		add("Ctrl-O", "open the command console")
+	}
+	add("Ctrl-C", "quit")
+
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			nav.pop()
+		}
+	})
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if keys.Help.Matches(event) {
+			nav.pop()
+			return nil
+		}
+		return event
+	})
+	table.SetBorder(true).SetTitle(" Keys (Esc to close) ")
+	nav.push(table)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// defaultHistoryMax is how many launches the history keeps by default
+const defaultHistoryMax = 1000
+
+// historyMax caps the history; older launches are trimmed from the file
+var historyMax = defaultHistoryMax
+
+// historyEntry is one launch in the history file
+type historyEntry struct {
+	Time time.Time `json:"time"`
+	GameEntry
+}
+
+var (
+	// historyMu serialises writes to the history file
+	historyMu sync.Mutex
+	// historyLines counts the file's entries once known, -1 until then
+	historyLines = -1
+)
+
+// historyPath is the append-only launch log, one JSON entry per line
+func historyPath() string {
+	return filepath.Join(configDir(), "history.jsonl")
+}
+
+// historyCSVPath is where the history is exported to
+func historyCSVPath() string {
+	return filepath.Join(configDir(), "history.csv")
+}
+
+// appendHistory adds a launch of entry at t to the history file, trimming
+// the oldest entries once it holds a tenth more than historyMax
+func appendHistory(entry GameEntry, t time.Time) error {
+	line, err := json.Marshal(historyEntry{Time: t, GameEntry: entry})
+	if err != nil {
+		return err
+	}
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	f, err := os.OpenFile(historyPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if historyLines < 0 {
+		historyLines = len(readHistory())
+	} else {
+		historyLines++
+	}
+	if historyMax > 0 && historyLines > historyMax+historyMax/10 {
+		return trimHistory()
+	}
+	return nil
+}
+
+// trimHistory rewrites the history file with only its newest historyMax
+// entries; the caller holds historyMu
+func trimHistory() error {
+	entries := readHistory()
+	if len(entries) > historyMax {
+		entries = entries[len(entries)-historyMax:]
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	if err := writeAtomic(historyPath(), []byte(b.String())); err != nil {
+		return err
+	}
+	historyLines = len(entries)
+	return nil
+}
+
+// readHistory returns the logged launches, oldest first, skipping lines
+// that don't parse
+func readHistory() []historyEntry {
+	f, err := os.Open(historyPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logWarn("%v", err)
+		}
+		return nil
+	}
+	defer f.Close()
+	var entries []historyEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e historyEntry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		logWarn("%s: %v", historyPath(), err)
+	}
+	return entries
+}
+
+// exportHistoryCSV writes the history to path as time, system, title, path
+func exportHistoryCSV(path string, entries []historyEntry) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"time", "system", "title", "path"})
+	for _, e := range entries {
+		w.Write([]string{e.Time.Format(time.RFC3339), e.System, e.Title, e.Path})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return writeAtomic(path, buf.Bytes())
+}
+
+// showHistory lists every logged launch, newest first. Enter launches the
+// highlighted game and 'e' exports the history to CSV.
+func showHistory(app *tview.Application) {
+	historyMu.Lock()
+	entries := readHistory()
+	historyMu.Unlock()
+	if len(entries) == 0 {
+		showMessage(app, T("msg.nothing_launched"))
+		return
+	}
+
+	list := tview.NewList()
+	styleList(list)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		when := e.Time.Local().Format("2006-01-02 15:04")
+		list.AddItem(tview.Escape(displayTitle(e.GameEntry)), when+"  "+tview.Escape(e.System), 0, func() {
+			nav.pop()
+			startGame(e.GameEntry)
+		})
+	}
+	list.SetDoneFunc(func() { nav.pop() })
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune || event.Rune() != 'e' {
+			return event
+		}
+		if err := exportHistoryCSV(historyCSVPath(), entries); err != nil {
+			showError(app, T("error.export_history"), err)
+		} else {
+			showMessage(app, fmt.Sprintf(T("msg.history_exported"), len(entries), historyCSVPath()))
+		}
+		return nil
+	})
+	list.SetBorder(true).SetTitle(" History (Enter launch, e export CSV, Esc close) ")
+	nav.push(list)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// defaultHookTimeout bounds a hook that sets no timeout, so a stuck command
+// can't hold up launching
+const defaultHookTimeout = 3 * time.Second
+
+// LaunchHooks are shell commands run around each launch, under "hooks:" in
+// systems.yaml. They see the game as PEEPER_TITLE, PEEPER_SYSTEM,
+// PEEPER_PATH and PEEPER_CORE. A failing pre_launch hook only stops the
+// launch when Strict is set. Transform rewrites each game's launch command,
+// see CommandTransform.
+type LaunchHooks struct {
+	PreLaunch  string `yaml:"pre_launch,omitempty"`
+	PostLaunch string `yaml:"post_launch,omitempty"`
+	Transform  string `yaml:"transform,omitempty"`
+	Strict     bool   `yaml:"strict,omitempty"`
+	Timeout    int    `yaml:"timeout,omitempty"`
+}
+
+// timeout is how long a hook may run before it is killed
+func (h LaunchHooks) timeout() time.Duration {
+	if h.Timeout > 0 {
+		return time.Duration(h.Timeout) * time.Second
+	}
+	return defaultHookTimeout
+}
+
+// runHook runs command for game with sh, killing it after the timeout or
+// when ctx ends. Its output is discarded, so processes it leaves behind
+// can't keep it open.
+func runHook(ctx context.Context, name, command string, game GameEntry, timeout time.Duration) error {
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"PEEPER_TITLE="+game.Title,
+		"PEEPER_SYSTEM="+game.System,
+		"PEEPER_PATH="+game.Path,
+		"PEEPER_CORE="+gameCore(game),
+	)
+	err := cmd.Run()
+	if parent.Err() != nil {
+		err = parent.Err()
+	} else if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %v", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%s hook: %w", name, err)
+	}
+	return nil
+}
+
+// preLaunch runs the pre_launch hook; its error is only returned in strict
+// mode, otherwise it is logged
+func preLaunch(ctx context.Context, game GameEntry) error {
+	h := cfg.Hooks
+	if h.PreLaunch == "" {
+		return nil
+	}
+	err := runHook(ctx, "pre_launch", h.PreLaunch, game, h.timeout())
+	if err != nil && !h.Strict {
+		logWarn("%s: %v", game.Title, err)
+		return nil
+	}
+	return err
+}
+
+// postLaunch runs the post_launch hook in the background, logging failures
+func postLaunch(game GameEntry) {
+	h := cfg.Hooks
+	if h.PostLaunch == "" {
+		return
+	}
+	go func() {
+		if err := runHook(context.Background(), "post_launch", h.PostLaunch, game, h.timeout()); err != nil {
+			logWarn("%s: %v", game.Title, err)
+		}
+	}()
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// liveStatus is the selection shared with the status server; tview callbacks
+// write it and HTTP handlers read it, so all access goes through the mutex
+type liveStatus struct {
+	mu         sync.Mutex
+	system     string
+	game       *GameEntry
+	launched   *GameEntry
+	launchedAt time.Time
+}
+
+// statusResponse is the JSON body served at /status
+type statusResponse struct {
+	System     string     `json:"system"`
+	Game       *GameEntry `json:"game"`
+	Launched   *GameEntry `json:"last_launched"`
+	LaunchedAt *time.Time `json:"launched_at,omitempty"`
+}
+
+var live liveStatus
+
+// setSelection records the open system and highlighted game (nil for none)
+func (s *liveStatus) setSelection(system string, game *GameEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.system, s.game = system, game
+}
+
+// setLaunched records a successful launch
+func (s *liveStatus) setLaunched(game GameEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.launched, s.launchedAt = &game, time.Now()
+}
+
+// snapshot copies the status for serving
+func (s *liveStatus) snapshot() statusResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := statusResponse{System: s.system, Game: s.game, Launched: s.launched}
+	if s.launched != nil {
+		at := s.launchedAt
+		r.LaunchedAt = &at
+	}
+	return r
+}
+
+// startStatusServer serves /status on addr in the background; listen errors
+// are returned straight away so a bad -http value fails before the UI starts
+func startStatusServer(addr string) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(live.snapshot())
+	})
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: networkTimeout,
+		WriteTimeout:      networkTimeout,
+	}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			logError("status server: %v", err)
+		}
+	}()
+	logInfo("status server listening on %s", ln.Addr())
+	return srv, nil
+}
+
+// stopStatusServer shuts the server down, waiting briefly for open requests
+func stopStatusServer(srv *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logWarn("status server shutdown: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// langCode picks the message catalog; -lang sets it, otherwise $LANG
+var langCode string
+
+// english is the built-in catalog, and the fallback for keys a translation
+// lacks. Entries with verbs are fmt formats.
+var english = map[string]string{
+	"button.ok":     "OK",
+	"button.cancel": "Cancel",
+	"button.clear":  "Clear",
+	"button.yes":    "Yes",
+	"button.no":     "No",
+	"button.reload": "Reload",
+	"button.later":  "Later",
+	"button.launch": "Launch",
+	"button.back":   "Back",
+
+	"launch.starting":   "Starting %s (%s)",
+	"launch.launching":  "Launching %s (%s)...",
+	"launch.would_send": "Would send: %s",
+	"launch.failed":     "Could not launch %s (%s)\n\nCommand: %s\nError: %s",
+
+	"msg.nothing_launched":  "Nothing launched yet",
+	"msg.nothing_to_pick":   "Nothing to pick",
+	"msg.history_exported":  "Exported %d launches to\n%s",
+	"msg.lists_exported":    "Exported %d favorites and %d recent games to\n%s",
+	"msg.unavailable":       "%s is unavailable: %s",
+	"msg.clear_recent":      "Clear all %d recent games?",
+	"msg.no_path":           "This entry has no file to copy",
+	"msg.nothing_to_export": "No games in this list to export",
+	"msg.export_list":       "Export this list, as shown, as",
+	"msg.list_exported":     "Exported %d games to\n%s",
+	"msg.copy_manually":     "No clipboard available, copy the path by hand:\n\n%s",
+	"msg.quit":              "Quit MiSter Peeper?",
+	"msg.scanning":          "Scanning %s...",
+	"msg.scan_progress":     "Scanning %s... %c\n\n%d games found",
+	"msg.verifying":         "Verifying...",
+	"msg.verify_progress":   "Verifying...\n\n%d games checked",
+	"msg.reload":            "Games directory changed to\n%s\n\nReload games now?",
+
+	"error.read_games":     "Could not read %s games",
+	"error.export":         "Could not export",
+	"error.export_history": "Could not export history",
+	"error.nothing_import": "Nothing to import",
+	"error.import":         "Could not import",
+	"error.save_settings":  "Could not save settings",
+	"error.save":           "Could not save %s",
+	"error.run":            "Could not run %s",
+
+	"item.show_all": "Show all %d…",
+
+	"hint.open":          "open",
+	"hint.games":         "games",
+	"hint.systems":       "systems",
+	"hint.launch":        "launch",
+	"hint.launch_exit":   "launch & exit",
+	"hint.favorite":      "favorite",
+	"hint.preview":       "preview",
+	"hint.paths":         "paths",
+	"hint.copy":          "copy path",
+	"hint.export":        "export list",
+	"hint.queue":         "queue",
+	"hint.hide_systems":  "hide systems",
+	"hint.show_systems":  "systems",
+	"hint.view":          "view",
+	"hint.search":        "search",
+	"hint.search_all":    "search all",
+	"hint.sort":          "sort",
+	"hint.refresh":       "refresh",
+	"hint.random":        "random",
+	"hint.history":       "history",
+	"hint.help":          "help",
+	"hint.quit":          "quit",
+	"hint.remove":        "remove",
+	"hint.clear":         "clear all",
+	"status.sort":        "sort: %s",
+	"status.letter_only": "only %s (Esc shows all)",
+	"status.copied":      "path copied",
+	"status.queued":      "queued (%d in queue)",
+}
+
+var (
+	// messages is the loaded translation, which may be missing keys
+	messages = map[string]string{}
+	// missingMu guards missingKeys, the keys already warned about
+	missingMu   sync.Mutex
+	missingKeys = map[string]bool{}
+)
+
+// T returns the text for key in the chosen language, falling back to
+// English and warning once per key the translation lacks
+func T(key string) string {
+	if text, ok := messages[key]; ok {
+		return text
+	}
+	text, ok := english[key]
+	if !ok {
+		text = key
+	}
+	if len(messages) > 0 || !ok {
+		missingMu.Lock()
+		if !missingKeys[key] {
+			missingKeys[key] = true
+			logWarn("lang %s: no message %q", langCode, key)
+		}
+		missingMu.Unlock()
+	}
+	return text
+}
+
+// langDir holds one <code>.yaml catalog per language, beside the config
+func langDir() string {
+	return filepath.Join(configDir(), "lang")
+}
+
+// localeCode turns a locale such as "de_DE.UTF-8" into "de_DE"; "C" and
+// "POSIX" are English
+func localeCode(locale string) string {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return "en"
+	}
+	return locale
+}
+
+// loadLang reads the catalog for langCode, or for $LANG when no -lang was
+// given, trying the full code and then just its language, e.g. de_DE.yaml
+// then de.yaml. English needs no file.
+func loadLang() {
+	if langCode == "" {
+		langCode = localeCode(os.Getenv("LANG"))
+	}
+	candidates := []string{langCode}
+	if i := strings.IndexByte(langCode, '_'); i > 0 {
+		candidates = append(candidates, langCode[:i])
+	}
+	for _, code := range candidates {
+		data, err := os.ReadFile(filepath.Join(langDir(), code+".yaml"))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				logWarn("lang: %v", err)
+			}
+			continue
+		}
+		var m map[string]string
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			logWarn("lang %s: %v", code, err)
+			return
+		}
+		messages = m
+		langCode = code
+		logInfo("using %s messages from %s", code, langDir())
+		return
+	}
+	if code := candidates[len(candidates)-1]; code != "en" {
+		logWarn("lang %s: no catalog in %s, using English", langCode, langDir())
+	}
+}
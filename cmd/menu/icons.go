@@ -0,0 +1,37 @@
+package main
+
+import "github.com/rivo/tview"
+
+// categoryIcons are the icons for systems that don't set their own
+var categoryIcons = map[string]string{
+	"Consoles": "🎮",
+	"Arcade":   "🕹",
+}
+
+// showIcons prefixes games with their system's icon; off for terminals that
+// draw emoji badly
+var showIcons = true
+
+// iconFor is the icon of a game's system: its "icon" setting, or the one
+// for its category. It is "" when icons are off or the system has none.
+func iconFor(system string) string {
+	if !showIcons {
+		return ""
+	}
+	sys, ok := cfg.findSystem(system)
+	if !ok {
+		return ""
+	}
+	if sys.Icon != "" {
+		return sys.Icon
+	}
+	return categoryIcons[sys.Category]
+}
+
+// iconPrefix is the escaped icon and a space to put before a game's title
+func iconPrefix(game GameEntry) string {
+	if icon := iconFor(game.System); icon != "" {
+		return tview.Escape(icon) + " "
+	}
+	return ""
+}
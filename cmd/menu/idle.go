@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Idle actions, set with settings.idle_action
+const (
+	idleDim    = "dim"
+	idleRandom = "random"
+)
+
+var (
+	// idleTimeout is how long without input before the idle action; 0 never.
+	// The attract mode goroutine reads it, so it is only set through
+	// setIdleTimeout.
+	idleTimeout int64
+	// idleAction is what happens on timeout: idleDim or idleRandom
+	idleAction = idleDim
+
+	// idleKick restarts the idle timer after input or a new timeout
+	idleKick = make(chan struct{}, 1)
+
+	// screensaver is the blank screen shown by idleDim, or nil
+	screensaver tview.Primitive
+)
+
+// setIdleTimeout changes the idle timeout and restarts the timer
+func setIdleTimeout(d time.Duration) {
+	atomic.StoreInt64(&idleTimeout, int64(d))
+	noteActivity()
+}
+
+// noteActivity restarts the idle timer; called for every key and mouse event
+func noteActivity() {
+	select {
+	case idleKick <- struct{}{}:
+	default: // a restart is already pending
+	}
+}
+
+// startAttractMode runs the idle timer until ctx ends. With no timeout the
+// timer is stopped entirely until one is set.
+func startAttractMode(ctx context.Context) {
+	timer := time.NewTimer(time.Hour)
+	arm := func() {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if d := time.Duration(atomic.LoadInt64(&idleTimeout)); d > 0 {
+			timer.Reset(d)
+		}
+	}
+	arm()
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-idleKick:
+			arm()
+		case <-timer.C:
+			ui.update(idleFired)
+			arm()
+		}
+	}
+}
+
+// idleFired carries out the idle action, unless a core is running, something
+// is open on top of the menu or a search is being typed
+func idleFired() {
+	if nowPlaying != "" || !nav.empty() || app.GetFocus() == searchBox {
+		return
+	}
+	logInfo("idle, %s", idleAction)
+	if idleAction == idleRandom {
+		launchRandom()
+		return
+	}
+	screensaver = tview.NewBox().SetBackgroundColor(tcell.ColorBlack)
+	nav.push(screensaver)
+}
+
+// wakeKey closes the screensaver on any key; the key itself is swallowed so
+// waking never does anything else
+func wakeKey(event *tcell.EventKey) *tcell.EventKey {
+	noteActivity()
+	if screensaver == nil {
+		return event
+	}
+	nav.close(screensaver)
+	screensaver = nil
+	return nil
+}
+
+// appMouse notes mouse activity and closes the screensaver on a click
+func appMouse(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+	noteActivity()
+	if screensaver != nil && action != tview.MouseMove {
+		nav.close(screensaver)
+		screensaver = nil
+		return nil, action
+	}
+	return event, action
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+// TestIdleFired checks the idle action runs only over the bare menu with no
+// core running
+func TestIdleFired(t *testing.T) {
+	startTestUI(t, "NES/Zelda.nes")
+	// reset closes whatever idling opened, on the UI goroutine
+	reset := func() {
+		setNowPlaying("")
+		idleAction = idleDim
+		screensaver = nil
+		for !nav.empty() {
+			nav.pop()
+		}
+	}
+	t.Cleanup(func() { ui.update(reset) })
+
+	for _, action := range []string{idleDim, idleRandom} {
+		tryUpdate(t, func() {
+			idleAction = action
+			setNowPlaying("Super Mario Bros.")
+			idleFired()
+			if !nav.empty() {
+				t.Errorf("idle %s opened a screen while a core is running", action)
+			}
+			reset()
+		})
+	}
+
+	tryUpdate(t, func() {
+		nav.push(searchBox)
+		idleFired()
+		if screensaver != nil {
+			t.Error("idle dimmed the screen over an open screen")
+		}
+		reset()
+	})
+
+	tryUpdate(t, func() {
+		idleFired()
+		if screensaver == nil || nav.top() != screensaver {
+			t.Error("idle didn't dim the bare menu")
+		}
+	})
+}
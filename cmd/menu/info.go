@@ -0,0 +1,81 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+var (
+	// infoFirst opens the info view on selecting a game instead of
+	// launching it, from settings.info_first
+	infoFirst bool
+
+	// infoView is the open info view, infoCoverBox the empty region its
+	// cover is painted over and infoCover the image for it
+	infoView     tview.Primitive
+	infoCoverBox *tview.Box
+	infoCover    string
+)
+
+// openGame is what selecting a game does: launch it, or with infoFirst show
+// it first so the right variant can be confirmed
+func openGame(game GameEntry) {
+	if infoFirst && !fastMode && game.System != scriptsSystem {
+		showGameInfo(app, game)
+		return
+	}
+	startGame(game)
+}
+
+// showGameInfo fills the screen with one game's cover, details and path
+// above a Launch button. Enter launches it and Esc goes back.
+func showGameInfo(app *tview.Application, entry GameEntry) {
+	details := tview.NewTextView().SetDynamicColors(true).SetWrap(true).
+		SetText(gameDetails(entry) + metadataDescription(entry))
+
+	body := tview.NewFlex()
+	if imageProto != protoNone {
+		infoCoverBox = tview.NewBox()
+		body.AddItem(infoCoverBox, 0, 1, false)
+	}
+	body.AddItem(details, 0, 1, false)
+
+	closeInfo := nav.pop
+	launch := func() {
+		closeInfo()
+		startGame(entry)
+	}
+	buttons := tview.NewForm().SetButtonsAlign(tview.AlignCenter).
+		AddButton(T("button.launch"), launch).
+		AddButton(T("button.back"), closeInfo)
+	buttons.SetCancelFunc(closeInfo)
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, false).
+		AddItem(buttons, 3, 0, true)
+	view.SetBorder(true).SetTitle(" " + tview.Escape(displayTitle(entry)) + " ")
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			closeInfo()
+			return nil
+		case tcell.KeyEnter:
+			// Enter launches from anywhere but the Back button
+			if _, button := buttons.GetFocusedItemIndex(); button == 1 {
+				closeInfo()
+			} else {
+				launch()
+			}
+			return nil
+		}
+		return event
+	})
+
+	infoView, infoCover = view, gameImage(entry)
+	nav.push(view)
+}
+
+// infoShown reports whether the info view is the screen on top
+func infoShown() bool {
+	return infoView != nil && nav.top() == infoView
+}
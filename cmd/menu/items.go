@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// ItemKind is what a game list row shows, which decides how it is drawn and
+// whether the cursor can rest on it
+type ItemKind int
+
+const (
+	// ItemGame is a game, or a title grouping its variants
+	ItemGame ItemKind = iota
+	// ItemHeader is a folder, folded and unfolded on Enter
+	ItemHeader
+	// ItemMore is the row that lifts maxItems
+	ItemMore
+	// ItemSeparator is a letter between runs of titles
+	ItemSeparator
+	// ItemPlaceholder stands in for the rows of an empty list
+	ItemPlaceholder
+)
+
+// itemStyles are the style tags each kind's main text is drawn with; games
+// are left plain. applyTheme colours headers and placeholders.
+var itemStyles = map[ItemKind]string{}
+
+// defaultItemStyles are itemStyles before a theme changes them
+var defaultItemStyles = map[ItemKind]string{
+	ItemHeader:      "[::b]",
+	ItemMore:        "[::b]",
+	ItemSeparator:   "[::b]",
+	ItemPlaceholder: "[gray]",
+}
+
+func init() {
+	setItemColors("", "")
+}
+
+// setItemColors styles headers and separators in header, and placeholders in
+// placeholder; "" keeps the default look
+func setItemColors(header, placeholder string) {
+	for k, v := range defaultItemStyles {
+		itemStyles[k] = v
+	}
+	if header != "" {
+		itemStyles[ItemHeader] = "[" + header + "::b]"
+		itemStyles[ItemSeparator] = "[" + header + "::b]"
+	}
+	if placeholder != "" {
+		itemStyles[ItemPlaceholder] = "[" + placeholder + "]"
+	}
+}
+
+// styleItem wraps text, already escaped, in kind's style
+func styleItem(kind ItemKind, text string) string {
+	style := itemStyles[kind]
+	if style == "" {
+		return text
+	}
+	return style + text + "[-::-]"
+}
+
+// selectable reports whether the cursor can rest on the row
+func (r gameRow) selectable() bool {
+	return r.kind != ItemSeparator && r.kind != ItemPlaceholder
+}
+
+// selectableRow reports whether the cursor can rest on game list row i.
+// Rows past ui.shownRows, such as an empty list's placeholder, are left alone.
+func selectableRow(i int) bool {
+	return i < 0 || i >= len(ui.shownRows) || ui.shownRows[i].selectable()
+}
+
+// activateRow does what Enter on game list row i does; rows that can't be
+// selected do nothing
+func activateRow(i int) {
+	if i < 0 || i >= gameList.GetItemCount() || (i < len(ui.shownRows) && !ui.shownRows[i].selectable()) {
+		return
+	}
+	if selected := gameList.GetItemSelectedFunc(i); selected != nil {
+		selected()
+	}
+}
+
+// addPlaceholderItem shows text in place of an empty list's rows
+func addPlaceholderItem(list itemList, text string) {
+	list.AddItem(styleItem(ItemPlaceholder, tview.Escape(text)), "", 0, nil)
+}
+
+// rowIndent is the indentation of a row at its depth in the folder tree
+func rowIndent(row gameRow) string {
+	return strings.Repeat("  ", row.depth)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// KeyBinding is a single key: a rune, or a special key such as Enter
+type KeyBinding struct {
+	Key  tcell.Key
+	Rune rune
+}
+
+// KeyMap holds the key for each rebindable action
+type KeyMap struct {
+	Launch    KeyBinding
+	Favorite  KeyBinding
+	Search    KeyBinding
+	SearchAll KeyBinding
+	Sort      KeyBinding
+	Refresh   KeyBinding
+	Random    KeyBinding
+	Preview   KeyBinding
+	Paths     KeyBinding
+	History   KeyBinding
+	View      KeyBinding
+	Help      KeyBinding
+	Disc      KeyBinding
+	Remove    KeyBinding
+	Clear     KeyBinding
+	Copy      KeyBinding
+	Export    KeyBinding
+	Systems   KeyBinding
+	Queue     KeyBinding
+	QueueList KeyBinding
+	Quit      KeyBinding
+}
+
+// keys is the key map in use
+var keys = defaultKeyMap()
+
+// defaultKeyMap is the built-in key map used without a keys.yaml
+func defaultKeyMap() KeyMap {
+	return KeyMap{
+		Launch:    KeyBinding{Key: tcell.KeyEnter},
+		Favorite:  runeKey('f'),
+		Search:    runeKey('/'),
+		SearchAll: runeKey('G'),
+		Sort:      runeKey('s'),
+		Refresh:   runeKey('r'),
+		Random:    runeKey('R'),
+		Preview:   runeKey('p'),
+		Paths:     runeKey('P'),
+		History:   runeKey('H'),
+		View:      runeKey('v'),
+		Help:      runeKey('?'),
+		Disc:      runeKey('D'),
+		Remove:    runeKey('d'),
+		Clear:     runeKey('X'),
+		Copy:      runeKey('y'),
+		Export:    runeKey('E'),
+		Systems:   runeKey('S'),
+		Queue:     runeKey('Q'),
+		QueueList: runeKey('L'),
+		Quit:      runeKey('q'),
+	}
+}
+
+// runeKey binds a printable character
+func runeKey(r rune) KeyBinding {
+	return KeyBinding{Key: tcell.KeyRune, Rune: r}
+}
+
+// actions maps the names used in keys.yaml to the bindings they set
+func (m *KeyMap) actions() map[string]*KeyBinding {
+	return map[string]*KeyBinding{
+		"launch":     &m.Launch,
+		"favorite":   &m.Favorite,
+		"search":     &m.Search,
+		"search_all": &m.SearchAll,
+		"sort":       &m.Sort,
+		"refresh":    &m.Refresh,
+		"random":     &m.Random,
+		"preview":    &m.Preview,
+		"paths":      &m.Paths,
+		"history":    &m.History,
+		"view":       &m.View,
+		"help":       &m.Help,
+		"disc":       &m.Disc,
+		"remove":     &m.Remove,
+		"clear":      &m.Clear,
+		"copy":       &m.Copy,
+		"export":     &m.Export,
+		"systems":    &m.Systems,
+		"queue":      &m.Queue,
+		"queue_list": &m.QueueList,
+		"quit":       &m.Quit,
+	}
+}
+
+// keysPath is the optional key binding file, next to the systems file
+func keysPath() string {
+	return filepath.Join(configDir(), "keys.yaml")
+}
+
+// loadKeys reads a key map of "action: key" lines from path over the
+// defaults. Unknown actions and keys are skipped with a warning; if two
+// actions share a key the whole file is ignored.
+func loadKeys(path string) KeyMap {
+	m := defaultKeyMap()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logWarn("%v", err)
+		}
+		return m
+	}
+	var raw map[string]string
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		logWarn("%s: %v, using default keys", path, err)
+		return m
+	}
+
+	actions := m.actions()
+	for name, value := range raw {
+		b, ok := actions[strings.ToLower(name)]
+		if !ok {
+			logWarn("%s: unknown action %q", path, name)
+			continue
+		}
+		key, err := parseKey(value)
+		if err != nil {
+			logWarn("%s: %s: %v", path, name, err)
+			continue
+		}
+		*b = key
+	}
+
+	if dups := m.duplicates(); len(dups) > 0 {
+		logWarn("%s: %s, using default keys", path, strings.Join(dups, "; "))
+		return defaultKeyMap()
+	}
+	return m
+}
+
+// duplicates describes every key bound to more than one action
+func (m *KeyMap) duplicates() []string {
+	byKey := map[KeyBinding][]string{}
+	for name, b := range m.actions() {
+		byKey[*b] = append(byKey[*b], name)
+	}
+	var dups []string
+	for b, names := range byKey {
+		if len(names) > 1 {
+			sort.Strings(names)
+			dups = append(dups, fmt.Sprintf("%s is bound to %s", b, strings.Join(names, " and ")))
+		}
+	}
+	sort.Strings(dups)
+	return dups
+}
+
+// parseKey reads a single character or a tcell key name like "Ctrl-R" or "F2"
+func parseKey(s string) (KeyBinding, error) {
+	if utf8.RuneCountInString(s) == 1 {
+		r, _ := utf8.DecodeRuneInString(s)
+		return runeKey(r), nil
+	}
+	for k, name := range tcell.KeyNames {
+		if strings.EqualFold(name, s) {
+			return KeyBinding{Key: k}, nil
+		}
+	}
+	return KeyBinding{}, fmt.Errorf("unknown key %q", s)
+}
+
+// Matches reports whether event is this key
+func (b KeyBinding) Matches(event *tcell.EventKey) bool {
+	if b.Key == tcell.KeyRune {
+		return event.Key() == tcell.KeyRune && event.Rune() == b.Rune
+	}
+	return event.Key() == b.Key
+}
+
+// String names the key as it is written in keys.yaml
+func (b KeyBinding) String() string {
+	if b.Key == tcell.KeyRune {
+		return string(b.Rune)
+	}
+	if name, ok := tcell.KeyNames[b.Key]; ok {
+		return name
+	}
+	return fmt.Sprintf("key %d", b.Key)
+}
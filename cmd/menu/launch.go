@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// cmdDevice is the MiSTer main binary's command FIFO
+const cmdDevice = "/dev/MiSTer_cmd"
+
+// launchCommand formats the MiSTer command that loads a game. An explicit
+// command wins; otherwise the game's core override or the system's default
+// core loads its ROM with any extra arguments.
+func launchCommand(game GameEntry) string {
+	if game.Command != "" {
+		return game.Command
+	}
+	cmd := "load_core " + gameCore(game)
+	if game.Path != "" {
+		cmd = "load_rom " + gameCore(game) + " " + game.Path
+	}
+	if len(game.Args) > 0 {
+		cmd += " " + strings.Join(game.Args, " ")
+	}
+	return cmd
+}
+
+// gameCore is the core a game loads with: its own override or its system's
+func gameCore(game GameEntry) string {
+	if game.Core != "" {
+		return game.Core
+	}
+	if sys, ok := cfg.findSystem(game.System); ok {
+		return sys.coreName()
+	}
+	return game.System
+}
+
+// Launcher is a way of getting a game started on a MiSTer
+type Launcher interface {
+	Launch(game GameEntry) error
+}
+
+// contextLauncher is a Launcher that talks over the network; its launches
+// run off the UI goroutine and can time out or be cancelled
+type contextLauncher interface {
+	Launcher
+	LaunchContext(ctx context.Context, game GameEntry) error
+}
+
+// launcher is the backend picked from the flags
+var launcher Launcher = &DeviceLauncher{Path: cmdDevice}
+
+// DeviceLauncher writes launch commands to the local command FIFO
+type DeviceLauncher struct {
+	Path string
+}
+
+// Launch loads a game through the FIFO
+func (d *DeviceLauncher) Launch(game GameEntry) error {
+	return sendCommand(d.Path, launchCommand(game))
+}
+
+// DryRunLauncher only logs and shows the command it would send
+type DryRunLauncher struct {
+	// Show displays the command; nil just logs it
+	Show func(msg string)
+}
+
+// Launch reports the command for game without sending it
+func (d *DryRunLauncher) Launch(game GameEntry) error {
+	logInfo("dry run: would send %q", launchCommand(game))
+	if d.Show != nil {
+		d.Show(fmt.Sprintf(T("launch.starting"), game.Title, game.System) + "\n\n" + launchCommandString(game))
+	}
+	return nil
+}
+
+// launchCommandString describes exactly what launching game would do: the
+// bytes written, where they go, and the core and ROM files they resolve to
+func launchCommandString(game GameEntry) string {
+	if game.System == scriptsSystem {
+		return "Runs: " + scriptLauncher.Shell + " " + game.Path
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sends: %q\n", launchCommand(game)+"\n")
+	if cfg.Hooks.Transform != "" {
+		fmt.Fprintf(&b, "Through: %s\n", cfg.Hooks.Transform)
+	}
+	switch l := launcher.(type) {
+	case *DeviceLauncher:
+		fmt.Fprintf(&b, "To: %s\n", l.Path)
+	case *RemoteLauncher:
+		fmt.Fprintf(&b, "To: %s on %s@%s\n", cmdDevice, l.User, l.Addr)
+	case *DryRunLauncher:
+		b.WriteString("To: nowhere (dry run)\n")
+	}
+	if game.Command == "" {
+		core := gameCore(game)
+		path := corePath(core)
+		if path == "" {
+			path = "not installed here"
+		}
+		fmt.Fprintf(&b, "Core: %s (%s)\n", core, path)
+	}
+	if game.Path != "" {
+		status := "found"
+		if !gameAvailable(game) {
+			status = "missing"
+		}
+		fmt.Fprintf(&b, "ROM: %s (%s)\n", game.Path, status)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// previewKey shows what launching the highlighted game would send, without
+// launching it
+func previewKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Preview.Matches(event) {
+		return event
+	}
+	if game, ok := highlightedGame(); ok {
+		showMessageFor(app, game.Title+" ("+game.System+")\n\n"+launchCommandString(game), 0)
+	}
+	return nil
+}
+
+// launchGame asks the MiSTer to load a game, between the launch hooks, and
+// returns the command sent, after any transform. On the device the core must
+// be installed, so a missing one is reported rather than sent.
+func launchGame(game GameEntry) (string, error) {
+	return launchGameContext(context.Background(), game)
+}
+
+// launchGameContext is launchGame that gives up when ctx ends, if the
+// launcher and hooks support that
+func launchGameContext(ctx context.Context, game GameEntry) (string, error) {
+	cmd := launchCommand(game)
+	if !systemAvailable(game.System) {
+		sys, _ := cfg.findSystem(game.System)
+		if reason := unavailableReason(sys); reason != "" {
+			return cmd, fmt.Errorf("%s is unavailable: %s", game.System, reason)
+		}
+	}
+	if missing := arcadeMissing(game); missing != nil {
+		return cmd, fmt.Errorf("%s is missing ROMs: %s", game.Title, strings.Join(missing, ", "))
+	}
+	if onDevice() && game.Command == "" {
+		if _, err := newestCore(gameCore(game)); err != nil {
+			return cmd, err
+		}
+	}
+	if err := preLaunch(ctx, game); err != nil {
+		return cmd, err
+	}
+	if err := ctx.Err(); err != nil {
+		return cmd, err // cancelled while the hook ran
+	}
+	l := gameLauncher()
+	sent := game
+	if t, ok := l.(*CommandTransform); ok {
+		// Transformed here rather than by t.Launch, so the command sent is known
+		var err error
+		if sent, err = t.rewrite(ctx, game); err != nil {
+			return cmd, err
+		}
+		cmd, l = launchCommand(sent), t.Next
+	}
+	var err error
+	if cl, ok := l.(contextLauncher); ok {
+		err = cl.LaunchContext(ctx, sent)
+	} else {
+		err = l.Launch(sent)
+	}
+	if err != nil {
+		return cmd, err
+	}
+	postLaunch(game)
+	return cmd, nil
+}
+
+// sendRetries and sendBackoff control retrying transient FIFO errors; the
+// wait doubles after each attempt
+const (
+	sendRetries = 3
+	sendBackoff = 50 * time.Millisecond
+)
+
+// sendCommand writes a command to the MiSTer FIFO at path, retrying briefly
+// while the device reports it is busy
+func sendCommand(path, cmd string) error {
+	var err error
+	wait := sendBackoff
+	for attempt := 1; attempt <= sendRetries; attempt++ {
+		if err = writeCommand(path, cmd); err == nil || !transientError(err) {
+			return err
+		}
+		if attempt < sendRetries {
+			logWarn("command FIFO busy (attempt %d/%d): %v", attempt, sendRetries, err)
+			time.Sleep(wait)
+			wait *= 2
+		}
+	}
+	return err
+}
+
+// transientError reports whether a FIFO error is worth retrying
+func transientError(err error) bool {
+	return errors.Is(err, syscall.EBUSY) || errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR)
+}
+
+// writeCommand writes a single command line to the MiSTer command FIFO
+func writeCommand(path, cmd string) error {
+	// O_NONBLOCK makes the open fail instead of hanging when nothing reads the FIFO
+	f, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(cmd + "\n")
+	return err
+}
+
+// launchKey starts the highlighted row when the launch key is rebound away
+// from Enter, which the list already handles
+func launchKey(event *tcell.EventKey) *tcell.EventKey {
+	if keys.Launch.Key == tcell.KeyEnter || !keys.Launch.Matches(event) {
+		return event
+	}
+	launchHighlighted()
+	return nil
+}
+
+// launchHighlighted does what selecting the highlighted row does: launch
+// the game, open the folder or pick a variant
+func launchHighlighted() {
+	activateRow(gameList.GetCurrentItem())
+}
+
+// startGame launches a game, first offering any save states to resume and
+// then asking how to boot it if its system has boot options (with -fast
+// neither is asked and the first boot option is used). Scripts run instead.
+func startGame(game GameEntry) {
+	if game.System == scriptsSystem {
+		runScript(game)
+		return
+	}
+	pickSaveState(game, startWithBoot)
+}
+
+// startWithBoot launches game, first asking how to boot it if its system
+// has boot options
+func startWithBoot(game GameEntry) {
+	if opts := bootOptionsFor(game.System); len(opts) > 0 {
+		if fastMode {
+			game = withBoot(game, opts[0])
+		} else {
+			showBootOptions(game, opts)
+			return
+		}
+	}
+	launchNow(game)
+}
+
+// launchNow launches a game as it is. Failures are logged and shown with the
+// command that was attempted, leaving the menu open; with -fast a successful
+// launch closes it.
+func launchNow(game GameEntry) {
+	if _, slow := gameLauncher().(contextLauncher); slow || cfg.Hooks.PreLaunch != "" {
+		launchInBackground(game)
+		return
+	}
+	cmd, err := launchGame(game)
+	launchDone(game, cmd, err)
+}
+
+// launchInBackground launches over the network, or through hooks, behind a
+// modal that can cancel it, so a slow MiSTer or hook never blocks the UI.
+// The launch gives up after launchTimeout.
+func launchInBackground(game GameEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), launchTimeout())
+	var finished int32
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf(T("launch.launching"), game.Title, game.System)).
+		AddButtons([]string{T("button.cancel")}).
+		SetDoneFunc(func(int, string) {
+			if atomic.CompareAndSwapInt32(&finished, 0, 1) {
+				cancel()
+				nav.pop()
+				logInfo("cancelled launching %s", game.Title)
+			}
+		})
+	nav.push(modal)
+
+	ui.spawn(func() {
+		cmd, err := launchGameContext(ctx, game)
+		cancel()
+		ui.update(func() {
+			if !atomic.CompareAndSwapInt32(&finished, 0, 1) {
+				return // cancelled while the result was queued
+			}
+			nav.close(modal)
+			launchDone(game, cmd, err)
+		})
+	})
+}
+
+// launchTimeout bounds a launch run in the background: the network timeout
+// plus time for the pre_launch and transform hooks, if set
+func launchTimeout() time.Duration {
+	h := cfg.Hooks
+	timeout := networkTimeout
+	if h.PreLaunch != "" {
+		timeout += h.timeout()
+	}
+	if h.Transform != "" {
+		timeout += h.timeout()
+	}
+	return timeout
+}
+
+// launchDone reports how launching game as cmd went
+func launchDone(game GameEntry, cmd string, err error) {
+	if err != nil {
+		logError("launching %s (%s): %q: %v", game.Title, game.System, cmd, err)
+		if os.IsNotExist(err) {
+			// No MiSTer here (e.g. testing on a desktop): show what would be sent
+			showMessage(app, fmt.Sprintf(T("launch.starting"), game.Title, game.System)+"\n\n"+
+				fmt.Sprintf(T("launch.would_send"), cmd)+"\n"+err.Error())
+		} else {
+			showMessageFor(app, fmt.Sprintf(T("launch.failed"), game.Title, game.System, cmd, err), 0)
+		}
+		return
+	}
+	gameLaunched(game, cmd)
+}
+
+// gameLaunched records a successful launch, sent as cmd; with -fast it
+// closes the menu
+func gameLaunched(game GameEntry, cmd string) {
+	logInfo("launched %s (%s): %q", game.Title, game.System, cmd)
+	live.setLaunched(game)
+	recordRecent(game)
+	incrementPlayCount(game)
+	if _, disc := discNumber(game.Title); disc || len(game.Discs) > 0 {
+		rememberDisc(game)
+	}
+	if err := appendHistory(game, time.Now()); err != nil {
+		reportSaveError("history", err)
+	}
+	if fastMode {
+		app.Stop()
+	}
+}
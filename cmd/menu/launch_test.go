@@ -0,0 +1,245 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// recordingLauncher records the games it is asked to launch, failing them
+// all with err if set
+type recordingLauncher struct {
+	mu       sync.Mutex
+	launched []GameEntry
+	err      error
+}
+
+func (l *recordingLauncher) Launch(game GameEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.launched = append(l.launched, game)
+	return l.err
+}
+
+func (l *recordingLauncher) games() []GameEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]GameEntry(nil), l.launched...)
+}
+
+// startTestUI builds the menu over a games folder holding files, given as
+// paths under it, and runs it on a simulated screen. Everything the test
+// does to the UI afterwards goes through ui.update, and the background work
+// it starts is waited for when the test ends.
+func startTestUI(t *testing.T, files ...string) tcell.SimulationScreen {
+	t.Helper()
+	dir := t.TempDir()
+	gamesDir = filepath.Join(dir, "games")
+	configPath = filepath.Join(dir, "systems.yaml")
+	for _, sys := range []string{"NES", "SNES", "Genesis"} {
+		if err := os.MkdirAll(filepath.Join(gamesDir, sys), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(gamesDir, f), []byte("rom"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg = defaultConfig()
+	currentSystem = ""
+	nav = navStack{}
+	romCacheMu.Lock()
+	romCache = map[string][]GameEntry{}
+	romCacheMu.Unlock()
+
+	if err := setupUI(); err != nil {
+		t.Fatal(err)
+	}
+	screen := tcell.NewSimulationScreen("UTF-8")
+	screen.SetSize(120, 40)
+	app.SetScreen(screen)
+	setRoot(mainLayout())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := app.Run(); err != nil {
+			t.Error(err)
+		}
+	}()
+	t.Cleanup(func() {
+		if uiWedged {
+			return // nothing can stop a UI goroutine that is stuck
+		}
+		// Background work must end before the next test swaps the globals
+		ui.update(func() {
+			stopMarquee()
+			watchSystem("")
+		})
+		ui.wait()
+		app.Stop()
+		<-done
+		app = nil
+	})
+	return screen
+}
+
+// uiWedged is set once a test finds the UI goroutine stuck
+var uiWedged bool
+
+// tryUpdate runs f through ui.update, failing the test instead of hanging
+// if the UI goroutine is stuck and no longer takes input
+func tryUpdate(t *testing.T, f func()) {
+	t.Helper()
+	ran := make(chan struct{})
+	go ui.update(func() {
+		f()
+		close(ran)
+	})
+	select {
+	case <-ran:
+	case <-time.After(2 * time.Second):
+		uiWedged = true
+		t.Fatal("the UI goroutine is stuck and takes no more input")
+	}
+}
+
+// openSystemForTest selects system in the system list and waits for its
+// games to be listed
+func openSystemForTest(t *testing.T, system string) {
+	t.Helper()
+	loaded := make(chan struct{})
+	ui.update(func() {
+		systemList.SetCurrentItem(systemRow(system))
+		loadGamesThen(system, func() { close(loaded) })
+	})
+	select {
+	case <-loaded:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s never finished loading", system)
+	}
+}
+
+// highlightForTest moves the game list to the game titled title
+func highlightForTest(t *testing.T, title string) {
+	t.Helper()
+	found := false
+	ui.update(func() {
+		for i, r := range ui.shownRows {
+			if r.isGame() && r.game.Title == title {
+				pagedGames.Select(i)
+				found = true
+				return
+			}
+		}
+	})
+	if !found {
+		t.Fatalf("%s is not listed", title)
+	}
+}
+
+// screenText is everything drawn on screen, row by row. The screen is read
+// on the UI goroutine, where nothing draws meanwhile.
+func screenText(screen tcell.SimulationScreen) string {
+	var b strings.Builder
+	ui.update(func() {
+		cells, w, _ := screen.GetContents()
+		for i, c := range cells {
+			if len(c.Runes) > 0 {
+				b.WriteRune(c.Runes[0])
+			} else {
+				b.WriteByte(' ')
+			}
+			if (i+1)%w == 0 {
+				b.WriteByte('\n')
+			}
+		}
+	})
+	return b.String()
+}
+
+func TestLaunchHighlightedGame(t *testing.T) {
+	rec := &recordingLauncher{}
+	old := launcher
+	launcher = rec
+	t.Cleanup(func() { launcher = old })
+	startTestUI(t, "NES/Contra.nes", "NES/Zelda.nes", "SNES/Mario.sfc")
+
+	openSystemForTest(t, "NES")
+	highlightForTest(t, "Zelda")
+	ui.update(launchHighlighted)
+
+	got := rec.games()
+	if len(got) != 1 {
+		t.Fatalf("launched %d games, want 1", len(got))
+	}
+	want := GameEntry{Title: "Zelda", System: "NES", Path: filepath.Join(gamesDir, "NES", "Zelda.nes")}
+	if !sameGame(got[0], want) {
+		t.Errorf("launched %+v, want %+v", got[0], want)
+	}
+	if cmd := launchCommand(got[0]); cmd != "load_rom NES "+want.Path {
+		t.Errorf("launch command = %q", cmd)
+	}
+	ui.update(func() {
+		if !nav.empty() {
+			t.Error("a screen is open over the menu after a good launch")
+		}
+	})
+}
+
+func TestLaunchInEmptySystem(t *testing.T) {
+	rec := &recordingLauncher{}
+	old := launcher
+	launcher = rec
+	t.Cleanup(func() { launcher = old })
+	screen := startTestUI(t, "NES/Zelda.nes")
+
+	openSystemForTest(t, "SNES")
+	ui.update(func() {
+		if len(ui.shownRows) != 0 {
+			t.Errorf("SNES lists %d rows, want none", len(ui.shownRows))
+		}
+		launchHighlighted()
+	})
+
+	if got := rec.games(); len(got) != 0 {
+		t.Errorf("launched %v from an empty system", got)
+	}
+	if text := screenText(screen); !strings.Contains(text, "(no games found)") {
+		t.Errorf("empty system doesn't say so:\n%s", text)
+	}
+}
+
+func TestLaunchFailureShowsModal(t *testing.T) {
+	rec := &recordingLauncher{err: errors.New("FIFO is wedged")}
+	old := launcher
+	launcher = rec
+	t.Cleanup(func() { launcher = old })
+	screen := startTestUI(t, "NES/Zelda.nes")
+
+	openSystemForTest(t, "NES")
+	highlightForTest(t, "Zelda")
+	ui.update(launchHighlighted)
+
+	if got := rec.games(); len(got) != 1 || got[0].Title != "Zelda" {
+		t.Fatalf("launched %v, want Zelda once", got)
+	}
+	ui.update(func() {
+		if _, ok := nav.top().(*tview.Modal); !ok {
+			t.Errorf("top screen is %T, want a modal", nav.top())
+		}
+	})
+	text := screenText(screen)
+	for _, want := range []string{"Could not launch Zelda", "FIFO is wedged"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("failure modal doesn't show %q:\n%s", want, text)
+		}
+	}
+}
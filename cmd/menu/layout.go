@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// narrowWidth is the screen width below which only one pane is shown
+const narrowWidth = 80
+
+// defaultPaneWeights size the systems, games and details panes
+var defaultPaneWeights = []int{1, 2, 1}
+
+// paneWeights are the relative widths of the systems, games and details
+// panes in the full layout; 0 hides the systems or details pane
+var paneWeights = defaultPaneWeights
+
+// checkPanes validates settings.panes: three proportions, none negative,
+// and the games pane always shown
+func checkPanes(p []int) error {
+	if len(p) != 3 {
+		return fmt.Errorf("panes needs 3 proportions (systems, games, details), got %d", len(p))
+	}
+	for _, w := range p {
+		if w < 0 {
+			return fmt.Errorf("panes %v: proportions can't be negative", p)
+		}
+	}
+	if p[1] == 0 {
+		return fmt.Errorf("panes %v: the games pane can't be hidden", p)
+	}
+	return nil
+}
+
+var (
+	// screenWidth is the width seen at the last draw
+	screenWidth int
+	// layoutNarrow and layoutPane describe the main layout last built
+	layoutNarrow bool
+	layoutPane   pane
+)
+
+// narrowLayout shows just the active pane above the status bar; Tab swaps
+// between the system list and the games
+func narrowLayout() *tview.Flex {
+	root := tview.NewFlex().SetDirection(tview.FlexRow)
+	if activePane == gamePane {
+		root.AddItem(breadcrumb, 1, 0, false).
+			AddItem(searchBox, 1, 0, false).
+			AddItem(gameListPane(), 0, 1, true)
+	} else {
+		root.AddItem(systemList, 0, 1, true)
+	}
+	return root.AddItem(statusBar, 1, 0, false)
+}
+
+// trackScreenSize notes the screen width before each draw and switches
+// between the narrow and full layouts when it crosses narrowWidth
+func trackScreenSize(screen tcell.Screen) bool {
+	ui.markDrawn()
+	screenWidth, _ = screen.Size()
+	if (screenWidth < narrowWidth) != layoutNarrow && mainShown() {
+		// SetRoot can't be called while drawing
+		go ui.update(relayout)
+	}
+	return false
+}
+
+// mainShown reports whether the main layout, rather than a modal or form,
+// is on screen
+func mainShown() bool {
+	return nav.empty()
+}
+
+// relayout rebuilds the main layout for the current width and pane,
+// keeping focus where it was
+func relayout() {
+	if !mainShown() {
+		return
+	}
+	focus := app.GetFocus()
+	setRoot(mainLayout())
+	app.SetFocus(focus)
+}
+
+// paneChanged swaps the visible pane in the narrow layout, and shows or
+// hides a hidden system list. It runs from focus callbacks, already on the
+// UI goroutine.
+func paneChanged() {
+	if (layoutNarrow || paneWeights[0] == 0) && layoutPane != activePane {
+		relayout()
+	}
+}
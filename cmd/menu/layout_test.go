@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// waitFor polls cond on the UI goroutine until it holds, failing the test
+// with what after two seconds. Injected keys arrive apart from updates, so
+// their effect has to be waited for.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		var ok bool
+		tryUpdate(t, func() { ok = cond() })
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// showsPane reports whether p has focus and is the pane laid out
+func showsPane(p pane) func() bool {
+	return func() bool { return activePane == p && layoutPane == p }
+}
+
+func TestTabInNarrowLayout(t *testing.T) {
+	screen := startTestUI(t, "NES/Zelda.nes")
+	screen.SetSize(60, 30)
+	ui.update(func() {}) // draw at the new size
+	waitFor(t, "the narrow layout", func() bool { return layoutNarrow })
+
+	screen.InjectKey(tcell.KeyTab, 0, tcell.ModNone)
+	waitFor(t, "the games after Tab", showsPane(gamePane))
+	screen.InjectKey(tcell.KeyTab, 0, tcell.ModNone)
+	waitFor(t, "the systems after a second Tab", showsPane(systemPane))
+}
+
+func TestTabWithSystemsHidden(t *testing.T) {
+	screen := startTestUI(t, "NES/Zelda.nes")
+	t.Cleanup(func() { paneWeights = defaultPaneWeights })
+	tryUpdate(t, func() {
+		paneWeights = []int{0, 3, 2}
+		relayout()
+	})
+
+	screen.InjectKey(tcell.KeyTab, 0, tcell.ModNone)
+	waitFor(t, "the games after Tab", showsPane(gamePane))
+	screen.InjectKey(tcell.KeyTab, 0, tcell.ModNone)
+	waitFor(t, "the systems after a second Tab", showsPane(systemPane))
+	tryUpdate(t, func() {
+		if layoutNarrow {
+			t.Error("120 columns gave the narrow layout")
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/rivo/tview"
+)
+
+// indexLetters are the entries of the letter index; '#' covers titles
+// starting with anything but a letter
+const indexLetters = "#ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// otherRegion stands in for '#', which region names can't contain
+const otherRegion = "_"
+
+var (
+	// showLetterIndex is set by -index
+	showLetterIndex bool
+	// letterIndex is the clickable A-Z column beside the game list
+	letterIndex *tview.TextView
+)
+
+// newLetterIndex creates the letter column; clicking a letter jumps to it
+func newLetterIndex() *tview.TextView {
+	tv := tview.NewTextView().SetDynamicColors(true).SetRegions(true).SetWrap(false)
+	tv.SetHighlightedFunc(func(added, _, _ []string) {
+		if len(added) == 0 {
+			return
+		}
+		tv.Highlight()
+		letter := added[0][0]
+		if letter == otherRegion[0] {
+			letter = '#'
+		}
+		if i := findRow(func() int { return indexFor(letter) }); i >= 0 {
+			pagedGames.Select(i)
+			app.SetFocus(gameList)
+		}
+	})
+	return tv
+}
+
+// gameListPane is the game list, with the letter index beside it if enabled
+func gameListPane() tview.Primitive {
+	if !showLetterIndex {
+		return gameListView()
+	}
+	return tview.NewFlex().
+		AddItem(gameListView(), 0, 1, true).
+		AddItem(letterIndex, 2, 0, false)
+}
+
+// indexFor returns the first row whose title starts with letter, in the
+// current sort order, or -1. '#' finds titles not starting with a letter.
+func indexFor(letter byte) int {
+	for i, r := range ui.shownRows {
+		if !r.isGame() {
+			continue
+		}
+		if titleLetter(rowTitle(r)) == letter {
+			return i
+		}
+	}
+	return -1
+}
+
+// titleLetter is the index entry a title files under
+func titleLetter(title string) byte {
+	for _, r := range normalizeTitle(title) {
+		if r >= 'a' && r <= 'z' {
+			return byte(unicode.ToUpper(r))
+		}
+		return '#'
+	}
+	return '#'
+}
+
+// refreshLetterIndex dims the letters that no listed game starts with
+func refreshLetterIndex() {
+	if letterIndex == nil {
+		return
+	}
+	present := map[byte]bool{}
+	for _, r := range allRows() {
+		if r.isGame() {
+			present[titleLetter(rowTitle(r))] = true
+		}
+	}
+	var b strings.Builder
+	for i := 0; i < len(indexLetters); i++ {
+		c := indexLetters[i]
+		if present[c] {
+			region := string(c)
+			if c == '#' {
+				region = otherRegion
+			}
+			fmt.Fprintf(&b, "[\"%s\"]%c[\"\"]\n", region, c)
+		} else {
+			fmt.Fprintf(&b, "[gray]%c[-]\n", c)
+		}
+	}
+	letterIndex.SetText(b.String())
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+const (
+	// logMaxSize is the size at which the log file is rotated
+	logMaxSize = 1 << 20
+	// logBackups is how many rotated files are kept (menu.log.1, .2, ...)
+	logBackups = 3
+)
+
+// setupLogging sends log output to path, or discards it when path is empty
+// so nothing is printed over the TUI
+func setupLogging(path string) error {
+	if path == "" {
+		log.SetOutput(io.Discard)
+		return nil
+	}
+	w, err := openRotatingFile(path)
+	if err != nil {
+		return err
+	}
+	log.SetOutput(w)
+	log.SetFlags(log.LstdFlags)
+	return nil
+}
+
+// logInfo records a routine event such as a launch
+func logInfo(format string, args ...interface{}) {
+	log.Printf("INFO  "+format, args...)
+}
+
+// logWarn records a problem the menu worked around
+func logWarn(format string, args ...interface{}) {
+	log.Printf("WARN  "+format, args...)
+}
+
+// logError records a failure the user was told about
+func logError(format string, args ...interface{}) {
+	log.Printf("ERROR "+format, args...)
+}
+
+// rotatingFile is an append-only log that rolls over at logMaxSize
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	size int64
+}
+
+// openRotatingFile opens path for appending
+func openRotatingFile(path string) (*rotatingFile, error) {
+	r := &rotatingFile{path: path}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if it would overflow the file
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size+int64(len(p)) > logMaxSize && r.size > 0 {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts menu.log -> menu.log.1 -> menu.log.2 ... and starts afresh
+func (r *rotatingFile) rotate() error {
+	r.f.Close()
+	for i := logBackups - 1; i > 0; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return r.open()
+}
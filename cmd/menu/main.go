@@ -1,86 +1,539 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// version is stamped at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+// Command-line settings
+var (
+	configPath  = "systems.yaml"
+	gamesDir    = "/media/fat/games"
+	themePreset string
+	httpAddr    string
+	controlPath string
+	showAll     bool
+	listSystem  string
+	listJSON    bool
+	fastMode    bool
+	catalogDB   string
+	buildIndex  bool
+	resume      bool
+	dumpConfig  bool
+	selfTest    bool
+
+	// messageTimeout closes informational modals by themselves; 0 waits
+	// for OK
+	messageTimeout time.Duration
+
+	// flagsSet records the flags given on the command line, which take
+	// precedence over saved settings
+	flagsSet = map[string]bool{}
+)
+
 // Global lists
 var (
 	systemList *tview.List
 	gameList   *tview.List
+	searchBox  *tview.InputField
+	cfg        *Config
+	app        *tview.Application
+
+	// currentSystem is the system whose games are listed
+	currentSystem string
+
+	// gameListSecondary shows each game's secondary text line
+	gameListSecondary = true
 )
 
+// parseFlags reads the command line, exiting on bad values
+func parseFlags() {
+	flag.StringVar(&gamesDir, "games-dir", gamesDir, "directory holding one ROM folder per system")
+	flag.StringVar(&configPath, "config", configPath, "path to the systems YAML file")
+	showVersion := flag.Bool("version", false, "print the version and exit")
+	logPath := flag.String("log", "", "write a log to this file (disabled when empty)")
+	flag.StringVar(&themePreset, "theme", "", "built-in theme: dark, light or amber")
+	flag.BoolVar(&showAll, "show-all", false, "list systems even when their core is not installed")
+	flag.BoolVar(&groupSystems, "group", false, "group the systems list under category headers")
+	flag.StringVar(&listSystem, "list", "", "print the games of a system and exit, without the menu")
+	flag.BoolVar(&listJSON, "json", false, "with -list, print JSON instead of titles")
+	flag.StringVar(&catalogDB, "db", "", "list games from this catalog database instead of scanning ROM folders")
+	flag.BoolVar(&buildIndex, "build-index", false, "build or update the catalog database (-db, or catalog.db beside the config) and exit")
+	flag.StringVar(&httpAddr, "http", "", "serve the current selection as JSON at this address, e.g. :8080")
+	flag.StringVar(&controlPath, "control", "", "accept JSON launch requests on this Unix socket, e.g. /run/peeper.sock")
+	flag.DurationVar(&networkTimeout, "timeout", networkTimeout, "give up on remote launches and network clients after this long")
+	remoteTarget := flag.String("remote", "", "launch on a MiSTer over SSH, as user@host[:port]")
+	flag.IntVar(&scanWorkers, "scan-workers", scanWorkers, "how many systems to scan at once in the background")
+	flag.StringVar(&gamepadDevice, "gamepad", gamepadDevice, "joystick device for controller navigation")
+	flag.BoolVar(&showLetterIndex, "index", false, "show an A-Z index beside the game list")
+	flag.BoolVar(&noMouse, "no-mouse", false, "don't use the mouse even if the terminal supports it")
+	flag.BoolVar(&noColor, "no-color", false, "use the terminal's own colours only")
+	flag.BoolVar(&fastMode, "fast", false, "launch on Enter without asking anything and exit the menu")
+	flag.BoolVar(&dumpConfig, "dump-config", false, "print the effective configuration as YAML and exit")
+	flag.BoolVar(&selfTest, "selftest", false, "check the config, ROM folders, cores, launcher and state folders, print a report and exit")
+	flag.BoolVar(&resume, "resume", false, "launch the most recently played game without the menu, if there is one")
+	flag.StringVar(&langCode, "lang", "", "language of the menu's messages, read from lang/<code>.yaml beside the config (default $LANG)")
+	flag.BoolVar(&devMode, "dev", false, "enable the raw command console on Ctrl-O")
+	dryRun := flag.Bool("dry-run", false, "show launch commands instead of sending them")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Println("menu", version)
+		os.Exit(0)
+	}
+	if flag.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected arguments: %v\n", flag.Args())
+		os.Exit(2)
+	}
+	if networkTimeout <= 0 {
+		fmt.Fprintln(os.Stderr, "-timeout must be positive")
+		os.Exit(2)
+	}
+	if _, ok := themePresets[themePreset]; themePreset != "" && !ok {
+		fmt.Fprintf(os.Stderr, "-theme %s: unknown theme\n", themePreset)
+		os.Exit(2)
+	}
+	switch {
+	case *dryRun && *remoteTarget != "":
+		fmt.Fprintln(os.Stderr, "-dry-run and -remote cannot be combined")
+		os.Exit(2)
+	case *dryRun:
+		launcher = &DryRunLauncher{Show: func(msg string) { showMessage(app, msg) }}
+	case *remoteTarget != "":
+		r, err := NewRemoteLauncher(*remoteTarget)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "-remote: %v\n", err)
+			os.Exit(2)
+		}
+		launcher = r
+	}
+	if err := setupLogging(*logPath); err != nil {
+		fmt.Fprintf(os.Stderr, "-log: %v\n", err)
+		os.Exit(2)
+	}
+	flag.Visit(func(f *flag.Flag) {
+		flagsSet[f.Name] = true
+		if f.Name != "games-dir" {
+			return
+		}
+		if info, err := os.Stat(gamesDir); err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "-games-dir %s: not a directory\n", gamesDir)
+			os.Exit(2)
+		}
+	})
+}
+
 func main() {
-	app := tview.NewApplication()
+	defer recoverMain()
+	parseFlags()
+	loadLang()
+
+	var err error
+	cfg, err = LoadConfig(configPath)
+	problems, _ := err.(*ConfigErrors)
+	if err != nil && problems == nil {
+		logWarn("%v, using built-in systems", err)
+		cfg = defaultConfig()
+	}
+	applySettings(cfg.Settings)
+	if more, ok := cfg.Validate().(*ConfigErrors); ok {
+		if problems == nil {
+			problems = more
+		} else {
+			problems.Problems = append(problems.Problems, more.Problems...)
+		}
+	}
+	if selfTest {
+		os.Exit(runSelfTest())
+	}
+	reportConfig(problems)
+
+	loadRecent()
+	loadFavorites()
+	loadPlayCounts()
+	loadRegionChoices()
+	loadDiscChoices()
+	keys = loadKeys(keysPath())
+
+	if dumpConfig {
+		if err := cfg.Dump(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "-dump-config: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if buildIndex {
+		path := catalogDB
+		if path == "" {
+			path = indexPath()
+		}
+		os.Exit(runBuildIndex(path))
+	}
+	if catalogDB != "" {
+		p, err := OpenSQLiteProvider(catalogDB)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "-db: %v\n", err)
+			os.Exit(2)
+		}
+		defer p.Close()
+		provider = p
+	}
+
+	if listSystem != "" {
+		os.Exit(runList(os.Stdout, listSystem, listJSON))
+	}
+	if resume {
+		if code, ok := resumeLast(); ok {
+			os.Exit(code)
+		}
+	}
+	runUI()
+}
+
+// reportConfig logs config warnings and, if any problem is fatal, lists
+// them all on stderr and exits
+func reportConfig(problems *ConfigErrors) {
+	if problems == nil {
+		return
+	}
+	for _, p := range problems.Problems {
+		if !p.Fatal {
+			logWarn("%s", p.String(problems.Path))
+		}
+	}
+	if !problems.Fatal() {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "problems in the systems file:")
+	for _, p := range problems.Problems {
+		if p.Fatal {
+			logError("%s", p.String(problems.Path))
+			fmt.Fprintln(os.Stderr, "  "+p.String(problems.Path))
+		}
+	}
+	os.Exit(2)
+}
+
+// runUI builds the widgets and runs the menu until it is quit
+func runUI() {
+	if err := setupUI(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	go watchNowPlaying(ctx)
+	go startGamepad(ctx, app)
+	go startAttractMode(ctx)
+
+	setRoot(mainLayout())
+	// Restore after the layout is in place so scan errors can show a modal
+	restoreSelection()
+
+	if err := app.EnableMouse(useMouse).Run(); err != nil {
+		logError("%v", err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	saveSelection()
+	if statusServer != nil {
+		stopStatusServer(statusServer)
+	}
+	if controlListener != nil {
+		stopControl(controlListener)
+	}
+}
+
+// statusServer and controlListener serve -http and -control while the menu
+// runs
+var (
+	statusServer    *http.Server
+	controlListener net.Listener
+)
+
+// setupUI creates the application and every widget and wires up their
+// handlers, without touching the terminal, so the menu can also be driven
+// through loadGamesThen and launchHighlighted with a fake launcher
+func setupUI() error {
+	app = tview.NewApplication()
+	ui.drawn = make(chan struct{})
+	probeTerminal()
+
+	theme, err := loadTheme(themePath(), themePreset)
+	if err != nil {
+		logWarn("%v, using default colours", err)
+	}
+	applyTheme(theme)
 
 	// Initialize lists
-	systemList = tview.NewList().
-		AddItem("NES", "Nintendo Entertainment System", 'n', func() {
-			loadGames(app, "NES")
-		}).
-		AddItem("SNES", "Super Nintendo", 's', func() {
-			loadGames(app, "SNES")
-		}).
-		AddItem("Genesis", "Sega Genesis / Mega Drive", 'g', func() {
-			loadGames(app, "Genesis")
-		})
+	systemList = styleList(tview.NewList())
+	buildSystemList(cfg)
 
-	gameList = tview.NewList()
+	gameList = styleList(tview.NewList()).ShowSecondaryText(gameListSecondary)
+	pagedGames = NewPagedGameList(gameList, addRowItem)
+	gridGames = NewGridGameView(gameList)
+	searchBox = newSearchBox()
+	breadcrumb = newBreadcrumb()
+	if showLetterIndex {
+		letterIndex = newLetterIndex()
+	}
+	statusBar = newStatusBar()
+	setupDetails()
+	trackFocus()
+	refreshStatusBar()
+
+	systemList.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		skipHeaderRow(index)
+		refreshStatusBar()
+	})
+	gameList.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		pagedGames.EnsureLoaded(index)
+		if !selectableRow(index) {
+			skipRow(gameList, index, &lastGameRow, func(i int) bool { return !selectableRow(i) })
+			return
+		}
+		lastGameRow = index
+		gameHighlighted()
+	})
+	systemList.SetInputCapture(systemListKeys)
+	gameList.SetInputCapture(gameListKeys)
+	gameList.SetMouseCapture(gameListMouse)
 
-	// Layout: systems on left, games on right
-	layout := tview.NewFlex().
-		AddItem(systemList, 0, 1, true).
-		AddItem(gameList, 0, 2, false)
+	app.SetInputCapture(guardedCapture(globalKeys))
+	app.SetMouseCapture(appMouse)
+	app.SetBeforeDrawFunc(trackScreenSize)
+	app.SetAfterDrawFunc(drawCover)
 
-	if err := app.SetRoot(layout, true).EnableMouse(true).Run(); err != nil {
-		log.Fatal(err)
+	if httpAddr != "" {
+		if statusServer, err = startStatusServer(httpAddr); err != nil {
+			return fmt.Errorf("-http: %w", err)
+		}
 	}
+	if controlPath != "" {
+		if controlListener, err = startControl(controlPath); err != nil {
+			return fmt.Errorf("-control: %w", err)
+		}
+	}
+	return nil
+}
+
+// mainLayout puts systems on the left, the searchable games in the middle,
+// details on the right and the status bar underneath, sized by paneWeights.
+// Narrow screens get one pane at a time instead.
+func mainLayout() *tview.Flex {
+	layoutNarrow = screenWidth > 0 && screenWidth < narrowWidth
+	layoutPane = activePane
+	if layoutNarrow {
+		return narrowLayout()
+	}
+
+	games := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(breadcrumb, 1, 0, false).
+		AddItem(searchBox, 1, 0, false).
+		AddItem(gameListPane(), 0, 1, false)
+
+	// A hidden system list still appears while a system is being picked.
+	// Weights are scaled by sidebarSteps so it can slide shut smoothly.
+	systems := paneWeights[0]
+	if systems == 0 && activePane == systemPane {
+		systems = paneWeights[1]
+	}
+	systems = systemsWeight(systems)
+	panes := tview.NewFlex()
+	if systems > 0 {
+		panes.AddItem(systemList, 0, systems, true)
+	}
+	panes.AddItem(games, 0, paneWeights[1]*sidebarSteps, false)
+	if paneWeights[2] > 0 {
+		panes.AddItem(detailsColumn, 0, paneWeights[2]*sidebarSteps, false)
+	}
+
+	return tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(panes, 0, 1, true).
+		AddItem(statusBar, 1, 0, false)
+}
+
+// gameHighlighted refreshes everything that follows the game selection
+func gameHighlighted() {
+	startMarquee(gameList.GetCurrentItem())
+	refreshStatusBar()
+	refreshBreadcrumb()
+	refreshDetails()
+
+	var game *GameEntry
+	if g, ok := highlightedGame(); ok {
+		game = &g
+	}
+	live.setSelection(currentSystem, game)
+}
+
+// gameListKeys handles the hotkeys available while browsing games
+func gameListKeys(event *tcell.EventKey) *tcell.EventKey {
+	if event = clearLetterFilterKey(event); event == nil {
+		return nil
+	}
+	if event = gridKey(event); event == nil {
+		return nil
+	}
+	pagingKey(event)
+	if event = focusKeys(event); event == nil {
+		return nil
+	}
+	if typeAheadActive() {
+		return typeAheadKey(event)
+	}
+	if event = launchKey(event); event == nil {
+		return nil
+	}
+	if event = previewKey(event); event == nil {
+		return nil
+	}
+	if event = pathsKey(event); event == nil {
+		return nil
+	}
+	if event = viewKey(event); event == nil {
+		return nil
+	}
+	if event = discKey(event); event == nil {
+		return nil
+	}
+	if event = recentKeys(event); event == nil {
+		return nil
+	}
+	if event = copyPathKey(event); event == nil {
+		return nil
+	}
+	if event = exportListKey(event); event == nil {
+		return nil
+	}
+	if event = systemsKey(event); event == nil {
+		return nil
+	}
+	if event = queueKey(event); event == nil {
+		return nil
+	}
+	if event = queueListKey(event); event == nil {
+		return nil
+	}
+	if event = favoriteKey(event); event == nil {
+		return nil
+	}
+	if event = refreshKey(event); event == nil {
+		return nil
+	}
+	if event = sortKey(event); event == nil {
+		return nil
+	}
+	if event = searchKey(event); event == nil {
+		return nil
+	}
+	if letterKeys == letterKeysFilter {
+		return letterFilterKey(event)
+	}
+	return typeAheadKey(event)
+}
+
+// systemListKeys handles the hotkeys available while picking a system
+func systemListKeys(event *tcell.EventKey) *tcell.EventKey {
+	if event = focusKeys(event); event == nil {
+		return nil
+	}
+	if event = systemsKey(event); event == nil {
+		return nil
+	}
+	if event = queueListKey(event); event == nil {
+		return nil
+	}
+	if event = refreshKey(event); event == nil {
+		return nil
+	}
+	return searchKey(event)
 }
 
 // loadGames fills the game list based on system
 func loadGames(app *tview.Application, system string) {
-	gameList.Clear()
-
-	switch system {
-	case "NES":
-		gameList.AddItem("Super Mario Bros.", "", 0, func() {
-			showMessage(app, "Starting Super Mario Bros. (NES)")
-		})
-		gameList.AddItem("The Legend of Zelda", "", 0, func() {
-			showMessage(app, "Starting Zelda (NES)")
-		})
+	loadGamesThen(system, nil)
+}
 
-	case "SNES":
-		gameList.AddItem("Super Mario World", "", 0, func() {
-			showMessage(app, "Starting Super Mario World (SNES)")
-		})
-		gameList.AddItem("Donkey Kong Country", "", 0, func() {
-			showMessage(app, "Starting Donkey Kong Country (SNES)")
-		})
+// showGames puts a system's games in the list, reporting any scan error
+func showGames(system string, games []GameEntry, err error) {
+	stopMarquee()
+	changed := system != currentSystem
+	if changed {
+		saveScroll()
+	}
+	gameList.Clear()
+	if changed {
+		letterFilter = 0
+		showAllRows = false
+		leaveBucket(system)
+		// A sort picked with the sort key comes back with its system
+		sortMode = systemSort(system)
+		preloadMetadata(system)
+	}
+	currentSystem = system
+	watchSystem(system)
+	if err != nil {
+		showError(app, fmt.Sprintf(T("error.read_games"), system), err)
+	}
 
-	case "Genesis":
-		gameList.AddItem("Sonic the Hedgehog", "", 0, func() {
-			showMessage(app, "Starting Sonic (Genesis)")
-		})
-		gameList.AddItem("Streets of Rage", "", 0, func() {
-			showMessage(app, "Starting Streets of Rage (Genesis)")
-		})
+	setSystemCount(system, len(games))
+	ui.gameCache = games
+	if !keepsOrder(system) {
+		sortGames(ui.gameCache, sortMode)
+	}
+	filterGames(searchBox.GetText())
+	if changed {
+		restoreScroll(system)
 	}
 }
 
-// showMessage displays a simple modal
+// showError logs err with its context and shows it in a modal, which
+// stays up until dismissed
+func showError(app *tview.Application, context string, err error) {
+	logError("%s: %v", context, err)
+	showMessageFor(app, context+": "+err.Error(), 0)
+}
+
+// showMessage displays a simple modal, closing it by itself after
+// messageTimeout when that is set
 func showMessage(app *tview.Application, msg string) {
-	modal := tview.NewModal().
-		SetText(msg).
-		AddButtons([]string{"OK"}).
+	showMessageFor(app, msg, messageTimeout)
+}
+
+// showMessageFor displays a modal that closes after timeout, or only when
+// dismissed if timeout is 0
+func showMessageFor(app *tview.Application, msg string, timeout time.Duration) {
+	var timer *time.Timer
+	modal := tview.NewModal()
+	modal.SetText(msg).
+		AddButtons([]string{T("button.ok")}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			// Return to the system/game view
-			layout := tview.NewFlex().
-				AddItem(systemList, 0, 1, true).
-				AddItem(gameList, 0, 2, false)
-			app.SetRoot(layout, true)
+			if timer != nil {
+				timer.Stop()
+			}
+			nav.pop()
 		})
-	app.SetRoot(modal, true)
+	nav.push(modal)
+
+	if timeout > 0 {
+		timer = time.AfterFunc(timeout, func() {
+			ui.update(func() {
+				// Only if it is still open
+				nav.close(modal)
+			})
+		})
+	}
 }
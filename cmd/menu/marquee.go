@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	// marqueeStep is how often a long highlighted title scrolls by one letter
+	marqueeStep = 250 * time.Millisecond
+	// marqueeGap separates the end of a scrolling title from its start
+	marqueeGap = "   "
+)
+
+// marquee is the highlighted row currently scrolling, if any. It is only
+// touched on the UI goroutine.
+var marquee struct {
+	active bool
+	index  int    // list row
+	text   string // the row's own main text, put back when scrolling stops
+	indent string
+	title  []rune
+	width  int
+	offset int
+	stop   chan struct{}
+}
+
+// startMarquee scrolls row i's title if it is too wide for the list, after
+// putting back whichever row was scrolling before
+func startMarquee(i int) {
+	stopMarquee()
+	row := i >= 0 && i < len(ui.shownRows) && ui.shownRows[i].isGame()
+	if !row || gridView {
+		return
+	}
+	r := ui.shownRows[i]
+	title := rowLabel(r)
+	indent := strings.Repeat("  ", r.depth) + iconPrefix(r.game)
+	_, _, w, _ := gameList.GetInnerRect()
+	width := w - tview.TaggedStringWidth(indent)
+	if width <= 0 || tview.TaggedStringWidth(tview.Escape(title)) <= width {
+		return
+	}
+
+	text, _ := gameList.GetItemText(i)
+	stop := make(chan struct{})
+	marquee.active, marquee.index, marquee.text = true, i, text
+	marquee.indent, marquee.title, marquee.width = indent, []rune(title+marqueeGap), width
+	marquee.offset, marquee.stop = 0, stop
+
+	ui.spawn(func() {
+		ticker := time.NewTicker(marqueeStep)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				ui.update(func() {
+					// Ignore ticks queued just before the marquee stopped
+					if marquee.active && marquee.stop == stop {
+						advanceMarquee()
+					}
+				})
+			}
+		}
+	})
+}
+
+// advanceMarquee scrolls the title by one letter, wrapping around
+func advanceMarquee() {
+	m := &marquee
+	m.offset = (m.offset + 1) % len(m.title)
+	window := make([]rune, 0, m.width)
+	for j := 0; len(window) < m.width && j < len(m.title)*2; j++ {
+		window = append(window, m.title[(m.offset+j)%len(m.title)])
+	}
+	_, secondary := gameList.GetItemText(m.index)
+	gameList.SetItemText(m.index, m.indent+tview.Escape(string(window)), secondary)
+}
+
+// stopMarquee ends any scrolling and restores the row's text. Call it
+// before the list's rows are replaced.
+func stopMarquee() {
+	if !marquee.active {
+		return
+	}
+	close(marquee.stop)
+	marquee.active = false
+	if marquee.index < gameList.GetItemCount() {
+		_, secondary := gameList.GetItemText(marquee.index)
+		gameList.SetItemText(marquee.index, marquee.text, secondary)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/rivo/tview"
+)
+
+// Metadata describes a game beyond its file
+type Metadata struct {
+	Name        string
+	Description string
+	Genre       string
+	Year        string
+	Developer   string
+}
+
+// MetadataSource looks up what is known about a game
+type MetadataSource interface {
+	Lookup(game GameEntry) (Metadata, bool)
+}
+
+// preloader is a MetadataSource that reads a system's metadata ahead of
+// Lookup, which knows nothing about a system until it is loaded
+type preloader interface {
+	Loaded(system string) bool
+	Preload(system string)
+}
+
+// metadata is the source the details pane asks
+var metadata MetadataSource = NewGamelistXML()
+
+// gamelistFile is the EmulationStation metadata file in each ROM folder
+const gamelistFile = "gamelist.xml"
+
+// GamelistXML reads EmulationStation gamelist.xml files, one per system's ROM
+// folder, each loaded by Preload before its games can be looked up
+type GamelistXML struct {
+	mu      sync.Mutex
+	systems map[string]*gamelist
+}
+
+// gamelist is one parsed file, indexed by ROM file name and by loose title
+type gamelist struct {
+	byFile  map[string]Metadata
+	byTitle map[string]Metadata
+}
+
+// NewGamelistXML makes a source with nothing loaded yet
+func NewGamelistXML() *GamelistXML {
+	return &GamelistXML{systems: map[string]*gamelist{}}
+}
+
+// Lookup finds game by its ROM's file name, then by its title with case,
+// accents, tags and punctuation ignored
+func (g *GamelistXML) Lookup(game GameEntry) (Metadata, bool) {
+	if game.Path == "" {
+		return Metadata{}, false
+	}
+	g.mu.Lock()
+	list, ok := g.systems[game.System]
+	g.mu.Unlock()
+	if !ok {
+		return Metadata{}, false
+	}
+	name := filepath.Base(game.Path)
+	if _, member, ok := splitArchivePath(game.Path); ok {
+		name = path.Base(member)
+	}
+	if m, ok := list.byFile[strings.ToLower(name)]; ok {
+		return m, true
+	}
+	m, ok := list.byTitle[looseTitle(strings.TrimSuffix(name, filepath.Ext(name)))]
+	return m, ok
+}
+
+// Loaded reports whether system's gamelist has been read
+func (g *GamelistXML) Loaded(system string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.systems[system]
+	return ok
+}
+
+// Preload reads system's gamelist unless it already has been. The file is
+// parsed unlocked, so lookups for other systems don't wait on it.
+func (g *GamelistXML) Preload(system string) {
+	if g.Loaded(system) {
+		return
+	}
+	list := readGamelist(system)
+	g.mu.Lock()
+	g.systems[system] = list
+	g.mu.Unlock()
+}
+
+// readGamelist parses a system's gamelist; a missing or broken file leaves
+// the system without metadata
+func readGamelist(system string) *gamelist {
+	list := &gamelist{byFile: map[string]Metadata{}, byTitle: map[string]Metadata{}}
+	sys, ok := cfg.findSystem(system)
+	if !ok {
+		return list
+	}
+	file := filepath.Join(sys.romDir(), gamelistFile)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logWarn("metadata: %v", err)
+		}
+		return list
+	}
+	var doc struct {
+		Games []struct {
+			Path        string `xml:"path"`
+			Name        string `xml:"name"`
+			Desc        string `xml:"desc"`
+			Genre       string `xml:"genre"`
+			ReleaseDate string `xml:"releasedate"`
+			Developer   string `xml:"developer"`
+		} `xml:"game"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		logWarn("metadata: %s: %v", file, err)
+		return list
+	}
+	for _, e := range doc.Games {
+		m := Metadata{
+			Name:        strings.TrimSpace(e.Name),
+			Description: strings.TrimSpace(e.Desc),
+			Genre:       strings.TrimSpace(e.Genre),
+			Developer:   strings.TrimSpace(e.Developer),
+		}
+		// Release dates are written like 19910821T000000
+		if len(e.ReleaseDate) >= 4 {
+			m.Year = e.ReleaseDate[:4]
+		}
+		base := path.Base(filepath.ToSlash(e.Path))
+		list.byFile[strings.ToLower(base)] = m
+		if key := looseTitle(strings.TrimSuffix(base, path.Ext(base))); key != "" {
+			list.byTitle[key] = m
+		}
+	}
+	logInfo("metadata: %d games from %s", len(doc.Games), file)
+	return list
+}
+
+// looseTitle reduces a title to its letters and digits, normalised and
+// without tags, so near-identical file names match
+func looseTitle(title string) string {
+	var b strings.Builder
+	for _, r := range normalizeTitle(baseTitle(title)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// metadataPending are the systems whose metadata is being read in the
+// background; only touched on the UI goroutine
+var metadataPending = map[string]bool{}
+
+// preloadMetadata reads system's metadata in the background when the source
+// hasn't yet, then refreshes the details pane to show it
+func preloadMetadata(system string) {
+	p, ok := metadata.(preloader)
+	if !ok || metadataPending[system] || p.Loaded(system) {
+		return
+	}
+	metadataPending[system] = true
+	ui.spawn(func() {
+		p.Preload(system)
+		ui.update(func() {
+			delete(metadataPending, system)
+			refreshDetails()
+		})
+	})
+}
+
+// metadataDetails is the details pane lines for game's genre, year and
+// developer, or "" when the source knows nothing about it
+func metadataDetails(game GameEntry) string {
+	m, ok := metadata.Lookup(game)
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	if m.Genre != "" {
+		fmt.Fprintf(&b, "Genre:    %s\n", tview.Escape(m.Genre))
+	}
+	if m.Year != "" {
+		fmt.Fprintf(&b, "Year:     %s\n", tview.Escape(m.Year))
+	}
+	if m.Developer != "" {
+		fmt.Fprintf(&b, "By:       %s\n", tview.Escape(m.Developer))
+	}
+	return b.String()
+}
+
+// metadataDescription is game's description for the end of the details
+// pane, or ""
+func metadataDescription(game GameEntry) string {
+	if m, ok := metadata.Lookup(game); ok && m.Description != "" {
+		return "\n" + tview.Escape(m.Description) + "\n"
+	}
+	return ""
+}
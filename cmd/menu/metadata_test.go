@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMetadataLoadsInBackground checks opening a system reads its gamelist
+// off the UI goroutine and then fills in the details pane
+func TestMetadataLoadsInBackground(t *testing.T) {
+	old := metadata
+	t.Cleanup(func() { metadata = old })
+	metadata = NewGamelistXML()
+	startTestUI(t, "NES/Zelda.nes")
+	xml := `<gameList><game><path>./Zelda.nes</path><desc>Find the Triforce.</desc></game></gameList>`
+	if err := os.WriteFile(filepath.Join(gamesDir, "NES", gamelistFile), []byte(xml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	openSystemForTest(t, "NES")
+	highlightForTest(t, "Zelda")
+	waitFor(t, "the description", func() bool {
+		return strings.Contains(detailsPane.GetText(true), "Find the Triforce.")
+	})
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// maxItems caps how many rows an unfiltered list starts with; 0 shows
+	// them all
+	maxItems int
+	// showAllRows lifts the cap until the system changes
+	showAllRows bool
+	// cappedRows holds every row while the list is cut short by maxItems,
+	// and is nil otherwise
+	cappedRows []gameRow
+)
+
+// capRows cuts an unfiltered list down to maxItems rows, ending with a row
+// that shows the rest. Searches always list every match.
+func capRows(rows []gameRow, query string) []gameRow {
+	cappedRows = nil
+	if maxItems <= 0 || showAllRows || strings.TrimSpace(query) != "" || len(rows) <= maxItems {
+		return rows
+	}
+	n := maxItems
+	for n > 0 && rows[n-1].kind == ItemSeparator {
+		n--
+	}
+	cappedRows = rows
+	out := make([]gameRow, n, n+1)
+	copy(out, rows[:n])
+	return append(out, gameRow{kind: ItemMore, more: countGames(rows)})
+}
+
+// countGames counts the game rows among rows
+func countGames(rows []gameRow) int {
+	n := 0
+	for _, r := range rows {
+		if r.isGame() {
+			n++
+		}
+	}
+	return n
+}
+
+// addMoreItem shows the row that lifts the cap when selected
+func addMoreItem(list itemList, row gameRow) {
+	list.AddItem(styleItem(ItemMore, fmt.Sprintf(T("item.show_all"), row.more)), "", 0, expandRows)
+}
+
+// expandRows lists every row, keeping the cursor where the "show all" row
+// was so the first newly shown row is highlighted
+func expandRows() {
+	if cappedRows == nil {
+		return
+	}
+	i := gameList.GetCurrentItem()
+	showAllRows = true
+	filterGames(searchBox.GetText())
+	pagedGames.Select(i)
+}
+
+// allRows are the list's rows including any hidden by the cap
+func allRows() []gameRow {
+	if cappedRows != nil {
+		return cappedRows
+	}
+	return ui.shownRows
+}
+
+// findRow runs find over the shown rows and, if it misses while the list
+// is capped, lists every row and tries again
+func findRow(find func() int) int {
+	if i := find(); i >= 0 || cappedRows == nil {
+		return i
+	}
+	showAllRows = true
+	filterGames(searchBox.GetText())
+	return find()
+}
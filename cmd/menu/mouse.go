@@ -0,0 +1,63 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// doubleClickWindow is the longest gap between two clicks that launches
+const doubleClickWindow = 400 * time.Millisecond
+
+var (
+	lastClickIndex = -1
+	lastClickTime  time.Time
+)
+
+// gameListMouse makes a single click only highlight a game and a double click
+// on the same game launch it. tview already drops clicks whose pointer moved
+// between press and release, so dragging to scroll never launches anything.
+func gameListMouse(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	if action != tview.MouseLeftClick && action != tview.MouseLeftDoubleClick {
+		return action, event
+	}
+	index := gameListIndexAt(event.Position())
+	if index < 0 {
+		return action, event
+	}
+	clickGame(index)
+	return tview.MouseConsumed, nil
+}
+
+// clickGame highlights the game at list row index, or launches it when it
+// was clicked just before
+func clickGame(index int) {
+	app.SetFocus(gameList)
+	now := time.Now()
+	if index == lastClickIndex && now.Sub(lastClickTime) <= doubleClickWindow {
+		lastClickIndex = -1
+		activateRow(index)
+		return
+	}
+	lastClickIndex, lastClickTime = index, now
+	gameList.SetCurrentItem(index)
+}
+
+// gameListIndexAt maps a screen position to a game list row, or -1
+func gameListIndexAt(x, y int) int {
+	rx, ry, width, height := gameList.GetInnerRect()
+	if x < rx || x >= rx+width || y < ry || y >= ry+height {
+		return -1
+	}
+	rowHeight := 1
+	if gameListSecondary {
+		rowHeight = 2 // main and secondary text
+	}
+	offset, _ := gameList.GetOffset()
+	index := offset + (y-ry)/rowHeight
+	if index >= gameList.GetItemCount() {
+		return -1
+	}
+	return index
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestGameListIndexAt(t *testing.T) {
+	t.Cleanup(func() { gameListSecondary = true })
+	startTestUI(t, "NES/Contra.nes", "NES/Gradius.nes", "NES/Metroid.nes")
+	openSystemForTest(t, "NES")
+
+	for _, secondary := range []bool{true, false} {
+		tryUpdate(t, func() {
+			gameListSecondary = secondary
+			gameList.ShowSecondaryText(secondary)
+			x, y, _, _ := gameList.GetInnerRect()
+			want := 1
+			if secondary {
+				want = 0
+			}
+			if got := gameListIndexAt(x, y+1); got != want {
+				t.Errorf("secondary text %v: second line maps to row %d, want %d", secondary, got, want)
+			}
+		})
+	}
+}
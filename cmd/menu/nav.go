@@ -0,0 +1,84 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// navScreen is a modal, form or picker opened over the main layout, with the
+// widget that had focus before it
+type navScreen struct {
+	root  tview.Primitive
+	focus tview.Primitive
+}
+
+// navStack holds the screens opened over the main layout, newest last, so
+// going back always lands where the user came from
+type navStack struct {
+	screens []navScreen
+}
+
+// nav is the application's navigation stack; only touched on the UI goroutine
+var nav navStack
+
+// push shows p over whatever is on screen
+func (n *navStack) push(p tview.Primitive) {
+	n.screens = append(n.screens, navScreen{root: p, focus: app.GetFocus()})
+	setRoot(p)
+}
+
+// pop closes the top screen, returning to the one beneath (or the main
+// layout) with focus back where it was
+func (n *navStack) pop() {
+	if len(n.screens) == 0 {
+		return
+	}
+	top := n.screens[len(n.screens)-1]
+	n.screens = n.screens[:len(n.screens)-1]
+	if len(n.screens) == 0 {
+		setRoot(mainLayout())
+	} else {
+		setRoot(n.screens[len(n.screens)-1].root)
+	}
+	if top.focus != nil {
+		app.SetFocus(top.focus)
+	}
+}
+
+// close pops p and anything opened over it; it does nothing if p has
+// already gone, so timers can close their own screen safely
+func (n *navStack) close(p tview.Primitive) {
+	for i, s := range n.screens {
+		if s.root == p {
+			for len(n.screens) > i {
+				n.pop()
+			}
+			return
+		}
+	}
+}
+
+// top is the screen on top, or nil when the main layout is shown
+func (n *navStack) top() tview.Primitive {
+	if len(n.screens) == 0 {
+		return nil
+	}
+	return n.screens[len(n.screens)-1].root
+}
+
+// empty reports whether the main layout is on screen
+func (n *navStack) empty() bool {
+	return len(n.screens) == 0
+}
+
+// backKey makes Backspace go back like Esc on any screen over the main
+// layout, except while typing into a field
+func backKey(event *tcell.EventKey) *tcell.EventKey {
+	if nav.empty() || (event.Key() != tcell.KeyBackspace && event.Key() != tcell.KeyBackspace2) {
+		return event
+	}
+	if _, typing := app.GetFocus().(*tview.InputField); typing {
+		return event
+	}
+	return tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rivo/tview"
+)
+
+// The MiSTer main binary keeps the running core and file in these files
+const (
+	nowPlayingDir   = "/tmp"
+	coreNameFile    = "CORENAME"
+	currentPathFile = "CURRENTPATH"
+)
+
+// nowPlaying is what the MiSTer reports running, or "" in its own menu or
+// off-device; only touched on the UI goroutine
+var nowPlaying string
+
+// coreRunning is 1 while nowPlaying names a core. The gamepad goroutine reads
+// it, so it is only set through setNowPlaying.
+var coreRunning int32
+
+// setNowPlaying changes nowPlaying and coreRunning with it
+func setNowPlaying(text string) {
+	nowPlaying = text
+	var running int32
+	if text != "" {
+		running = 1
+	}
+	atomic.StoreInt32(&coreRunning, running)
+}
+
+// watchNowPlaying keeps nowPlaying in step with the MiSTer's status files
+// until ctx ends. Without the files, or a way to watch them, it stays empty.
+func watchNowPlaying(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logWarn("now playing: %v", err)
+		return
+	}
+	defer watcher.Close()
+	// Watch the folder, since the files come and go with each core
+	if err := watcher.Add(nowPlayingDir); err != nil {
+		logWarn("now playing: %v", err)
+		return
+	}
+
+	show := func() {
+		text := readNowPlaying()
+		ui.update(func() {
+			if text != nowPlaying {
+				setNowPlaying(text)
+				refreshStatusBar()
+			}
+		})
+	}
+	show()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if name := filepath.Base(event.Name); name == coreNameFile || name == currentPathFile {
+				show()
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logWarn("now playing: %v", err)
+		}
+	}
+}
+
+// readNowPlaying formats the running core and file as "core: file"
+func readNowPlaying() string {
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(nowPlayingDir, name))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	core := read(coreNameFile)
+	if core == "" || core == "MENU" {
+		return ""
+	}
+	if file := read(currentPathFile); file != "" {
+		return core + ": " + strings.TrimSuffix(file, filepath.Ext(file))
+	}
+	return core
+}
+
+// nowPlayingText is the status bar prefix for what is running
+func nowPlayingText() string {
+	if nowPlaying == "" {
+		return ""
+	}
+	return "[green]▶ " + tview.Escape(nowPlaying) + "[-]  "
+}
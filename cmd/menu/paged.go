@@ -0,0 +1,92 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	// gamePageSize is how many rows are added to the list at a time
+	gamePageSize = 200
+	// gamePageBuffer is how close to the last loaded row the selection may
+	// get before the next page is appended
+	gamePageBuffer = 50
+)
+
+// itemList is the part of tview.List the game list code needs, so it can
+// be driven without a terminal
+type itemList interface {
+	AddItem(mainText, secondaryText string, shortcut rune, selected func()) *tview.List
+	Clear() *tview.List
+	SetCurrentItem(index int) *tview.List
+	GetCurrentItem() int
+	GetItemCount() int
+}
+
+// PagedGameList fills a tview.List from a backing slice a page at a time so
+// huge systems don't need thousands of AddItem calls up front. List items
+// always form a prefix of the rows, so list indices match row indices.
+type PagedGameList struct {
+	list    itemList
+	entries []gameRow
+	loaded  int
+	addItem func(itemList, gameRow)
+}
+
+// NewPagedGameList wraps list, using addItem to build each row
+func NewPagedGameList(list itemList, addItem func(itemList, gameRow)) *PagedGameList {
+	return &PagedGameList{list: list, addItem: addItem}
+}
+
+// SetRows replaces the backing rows and shows the first page
+func (p *PagedGameList) SetRows(rows []gameRow) {
+	p.list.Clear()
+	p.entries = rows
+	p.loaded = 0
+	p.EnsureLoaded(0)
+}
+
+// EnsureLoaded appends pages until index is comfortably inside the list
+func (p *PagedGameList) EnsureLoaded(index int) {
+	for p.loaded < len(p.entries) && index+gamePageBuffer >= p.loaded {
+		end := p.loaded + gamePageSize
+		if end > len(p.entries) {
+			end = len(p.entries)
+		}
+		// Adding the first item fires the list's changed func, which calls
+		// back in here, so count the page as loaded before adding it
+		start := p.loaded
+		p.loaded = end
+		for _, row := range p.entries[start:end] {
+			p.addItem(p.list, row)
+		}
+	}
+}
+
+// Len is the number of backing rows, loaded or not
+func (p *PagedGameList) Len() int {
+	return len(p.entries)
+}
+
+// List is the list the rows are shown in
+func (p *PagedGameList) List() itemList {
+	return p.list
+}
+
+// Select highlights entry index, loading pages up to it first
+func (p *PagedGameList) Select(index int) {
+	p.EnsureLoaded(index)
+	p.list.SetCurrentItem(index)
+}
+
+// pagingKey loads the rows End and PgDn jump to before the list handles
+// them, as tview stops both at the last row it holds
+func pagingKey(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEnd:
+		pagedGames.EnsureLoaded(pagedGames.Len())
+	case tcell.KeyPgDn:
+		_, _, _, height := gameList.GetInnerRect()
+		pagedGames.EnsureLoaded(gameList.GetCurrentItem() + height)
+	}
+}
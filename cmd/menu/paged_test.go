@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// fakeItem is one row added to a fakeList
+type fakeItem struct {
+	main, secondary string
+	selected        func()
+}
+
+// fakeList is an itemList that records its rows instead of drawing them
+type fakeList struct {
+	items   []fakeItem
+	current int
+}
+
+func (l *fakeList) AddItem(mainText, secondaryText string, _ rune, selected func()) *tview.List {
+	l.items = append(l.items, fakeItem{mainText, secondaryText, selected})
+	return nil
+}
+
+func (l *fakeList) Clear() *tview.List {
+	l.items, l.current = nil, 0
+	return nil
+}
+
+func (l *fakeList) SetCurrentItem(index int) *tview.List {
+	l.current = index
+	return nil
+}
+
+func (l *fakeList) GetCurrentItem() int { return l.current }
+
+func (l *fakeList) GetItemCount() int { return len(l.items) }
+
+// mains is the main text of every row
+func (l *fakeList) mains() []string {
+	var out []string
+	for _, it := range l.items {
+		out = append(out, it.main)
+	}
+	return out
+}
+
+// setupRowTest lists games as the NES system with plain rows
+func setupRowTest(t *testing.T) {
+	t.Helper()
+	configPath = t.TempDir() + "/systems.yaml"
+	cfg = defaultConfig()
+	currentSystem = "NES"
+	showIcons = false
+	secondaryTemplate = ""
+	collapsed = map[string]bool{}
+	t.Cleanup(func() { showIcons = true })
+}
+
+func TestRenderRowsFolderTree(t *testing.T) {
+	setupRowTest(t)
+	games := []GameEntry{
+		{Title: "Zelda", System: "NES", Path: "/nes/Zelda.nes"},
+		{Title: "Contra", System: "NES", Path: "/nes/Japan/Contra.nes", Folder: "Japan"},
+		{Title: "Gradius", System: "NES", Path: "/nes/Japan/Hacks/Gradius.nes", Folder: "Japan/Hacks"},
+	}
+	rows := buildRows(games, "NES", "")
+	var kinds []ItemKind
+	for _, r := range rows {
+		kinds = append(kinds, r.kind)
+	}
+	wantKinds := []ItemKind{ItemHeader, ItemHeader, ItemGame, ItemGame, ItemGame}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Fatalf("row kinds = %v, want %v", kinds, wantKinds)
+	}
+
+	list := &fakeList{}
+	renderRows(NewPagedGameList(list, addRowItem), rows, nil, "")
+	want := []string{
+		"[::b]▾ Japan/[-::-]",
+		"  [::b]▾ Hacks/[-::-]",
+		"    Gradius",
+		"  Contra",
+		"Zelda",
+	}
+	if got := list.mains(); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+	if got := list.items[0].secondary; got != "  2 games" {
+		t.Errorf("folder detail = %q, want %q", got, "  2 games")
+	}
+
+	// A collapsed folder hides what is in it
+	collapsed[folderKey("NES", "Japan")] = true
+	renderRows(NewPagedGameList(list, addRowItem), buildRows(games, "NES", ""), nil, "")
+	want = []string{"[::b]▸ Japan/[-::-]", "Zelda"}
+	if got := list.mains(); !reflect.DeepEqual(got, want) {
+		t.Errorf("collapsed rows = %q, want %q", got, want)
+	}
+}
+
+func TestRenderRowsFlatWhenSearching(t *testing.T) {
+	setupRowTest(t)
+	games := []GameEntry{
+		{Title: "Contra", System: "NES", Path: "/nes/Japan/Contra.nes", Folder: "Japan"},
+		{Title: "Zelda", System: "NES", Path: "/nes/Zelda.nes"},
+	}
+	list := &fakeList{}
+	renderRows(NewPagedGameList(list, addRowItem), buildRows(games, "NES", "a"), nil, "a")
+	want := []string{"Contra", "Zelda"}
+	if got := list.mains(); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
+
+func TestRenderRowsPlaceholder(t *testing.T) {
+	setupRowTest(t)
+	for _, tc := range []struct {
+		query, want string
+	}{
+		{"", "[gray](no games found)[-::-]"},
+		{"zzz", "[gray](no matches)[-::-]"},
+	} {
+		list := &fakeList{}
+		renderRows(NewPagedGameList(list, addRowItem), nil, nil, tc.query)
+		if got := list.mains(); !reflect.DeepEqual(got, []string{tc.want}) {
+			t.Errorf("query %q: rows = %q, want [%q]", tc.query, got, tc.want)
+		}
+		if list.items[0].selected != nil {
+			t.Errorf("query %q: placeholder can be selected", tc.query)
+		}
+	}
+}
+
+func TestPagedGameListLoadsPages(t *testing.T) {
+	setupRowTest(t)
+	var rows []gameRow
+	for i := 0; i < 450; i++ {
+		rows = append(rows, gameRow{game: GameEntry{Title: fmt.Sprintf("Game %03d", i), System: "NES"}})
+	}
+	list := &fakeList{}
+	p := NewPagedGameList(list, addRowItem)
+	p.SetRows(rows)
+	if n := list.GetItemCount(); n != gamePageSize {
+		t.Fatalf("first page has %d rows, want %d", n, gamePageSize)
+	}
+	p.Select(gamePageSize - gamePageBuffer)
+	if n := list.GetItemCount(); n != 2*gamePageSize {
+		t.Errorf("after selecting near the end, %d rows loaded, want %d", n, 2*gamePageSize)
+	}
+	p.Select(449)
+	if n, cur := list.GetItemCount(), list.GetCurrentItem(); n != 450 || cur != 449 {
+		t.Errorf("after selecting the last row, %d rows with %d current, want 450 and 449", n, cur)
+	}
+	if got := list.items[449].main; got != "Game 449" {
+		t.Errorf("last row = %q, want %q", got, "Game 449")
+	}
+}
+
+// TestEndReachesUnloadedRows presses End with only the first page loaded,
+// which must land on the system's last game rather than the last loaded one
+func TestEndReachesUnloadedRows(t *testing.T) {
+	const games = gamePageSize + 2*gamePageBuffer
+	var files []string
+	for i := 0; i < games; i++ {
+		files = append(files, fmt.Sprintf("NES/Game %03d.nes", i))
+	}
+	screen := startTestUI(t, files...)
+	openSystemForTest(t, "NES")
+	screen.InjectKey(tcell.KeyTab, 0, tcell.ModNone)
+	waitFor(t, "the games after Tab", func() bool { return activePane == gamePane })
+
+	screen.InjectKey(tcell.KeyEnd, 0, tcell.ModNone)
+	last := len(ui.shownRows) - 1
+	waitFor(t, "End to reach the last game", func() bool { return gameList.GetCurrentItem() == last })
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// showPaths lists games by their absolute file path instead of their title
+var showPaths bool
+
+// rowLabel is the main text of a game row: its title, or with showPaths the
+// file it launches. Grouped variants keep their shared title.
+func rowLabel(row gameRow) string {
+	if !showPaths || row.variants != nil || row.game.Path == "" {
+		return rowTitle(row)
+	}
+	if abs, err := filepath.Abs(row.game.Path); err == nil {
+		return abs
+	}
+	return row.game.Path
+}
+
+// pathsKey switches between titles and paths on the paths key ('P'),
+// redrawing the list in place and saving the choice
+func pathsKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Paths.Matches(event) {
+		return event
+	}
+	showPaths = !showPaths
+	filterGames(searchBox.GetText())
+
+	saveSetting(func(s *Settings) { s.ShowPaths = showPaths })
+	return nil
+}
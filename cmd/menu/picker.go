@@ -0,0 +1,35 @@
+package main
+
+import "github.com/rivo/tview"
+
+// showPicker opens a small centered list titled title, starting at item
+// start. Picking an item closes the list and calls choose with its index;
+// Esc just closes it.
+func showPicker(title string, labels []string, start int, choose func(i int)) {
+	list := styleList(tview.NewList()).ShowSecondaryText(false)
+	for i, label := range labels {
+		i := i
+		list.AddItem(tview.Escape(label), "", 0, func() {
+			nav.pop()
+			choose(i)
+		})
+	}
+	list.SetCurrentItem(start)
+	list.SetDoneFunc(nav.pop)
+	list.SetBorder(true).SetTitle(" " + tview.Escape(title) + " ")
+
+	width := len(title) + 4
+	for _, label := range labels {
+		if n := len(label) + 4; n > width {
+			width = n
+		}
+	}
+	frame := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(list, len(labels)+2, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+	nav.push(frame)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	mostPlayedSystem = "Most Played"
+	maxMostPlayed    = 50
+)
+
+// playCount is how often one game has been launched, with the entry it was
+// last launched as
+type playCount struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+	Game  GameEntry `json:"game"`
+}
+
+// playCounts are the launch counts keyed by playKey and saved in
+// playcounts.json
+var playCounts = map[string]*playCount{}
+
+// playCountsPath is the file launch counts are saved to
+func playCountsPath() string {
+	return filepath.Join(configDir(), "playcounts.json")
+}
+
+// loadPlayCounts reads playcounts.json, leaving no counts if it is missing
+func loadPlayCounts() {
+	loadJSON(playCountsPath(), &playCounts)
+	if playCounts == nil {
+		playCounts = map[string]*playCount{}
+	}
+}
+
+// playKey identifies a game by system and base title, so renaming or
+// moving its file, or switching region, keeps the count
+func playKey(g GameEntry) string {
+	return g.System + "/" + strings.ToLower(baseTitle(g.Title))
+}
+
+// incrementPlayCount counts a launch of entry and saves the counts
+func incrementPlayCount(entry GameEntry) {
+	key := playKey(entry)
+	c := playCounts[key]
+	if c == nil {
+		c = &playCount{}
+		playCounts[key] = c
+	}
+	c.Count++
+	c.Last = time.Now()
+	c.Game = entry
+
+	if err := saveJSON(playCountsPath(), playCounts); err != nil {
+		reportSaveError("play counts", err)
+	}
+	setSystemCount(mostPlayedSystem, mostPlayedCount())
+
+	if currentSystem == mostPlayedSystem {
+		loadGames(app, mostPlayedSystem)
+	}
+}
+
+// timesPlayed is how often game has been launched
+func timesPlayed(game GameEntry) int {
+	if c := playCounts[playKey(game)]; c != nil {
+		return c.Count
+	}
+	return 0
+}
+
+// mostPlayedCount is how many games the Most Played list shows
+func mostPlayedCount() int {
+	if len(playCounts) > maxMostPlayed {
+		return maxMostPlayed
+	}
+	return len(playCounts)
+}
+
+// topPlayed returns up to n games, most launched first and the more
+// recently played first among equal counts
+func topPlayed(n int) []GameEntry {
+	counts := make([]*playCount, 0, len(playCounts))
+	for _, c := range playCounts {
+		counts = append(counts, c)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Last.After(counts[j].Last)
+	})
+	if n >= 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	games := make([]GameEntry, len(counts))
+	for i, c := range counts {
+		games[i] = c.Game
+	}
+	return games
+}
+
+// playDetails is the details pane line giving game's launch count
+func playDetails(game GameEntry) string {
+	switch n := timesPlayed(game); n {
+	case 0:
+		return ""
+	case 1:
+		return "Played:   once\n"
+	default:
+		return fmt.Sprintf("Played:   %d times\n", n)
+	}
+}
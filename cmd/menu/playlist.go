@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// playlistDir holds .m3u collections, each listed as its own system
+func playlistDir() string {
+	return filepath.Join(configDir(), "playlists")
+}
+
+var (
+	// playlistFiles maps each playlist's name to its file, as found by the
+	// last loadPlaylists
+	playlistFiles map[string]string
+	// playlistMu guards playlistFiles, which background scans read
+	playlistMu sync.Mutex
+)
+
+// playlists maps each playlist's name to its file. The map is shared, so
+// callers must not change it.
+func playlists() map[string]string {
+	playlistMu.Lock()
+	defer playlistMu.Unlock()
+	return playlistFiles
+}
+
+// loadPlaylists finds the playlists again; the system list calls it each
+// time it is built, so the folder is read and its clashes logged once
+func loadPlaylists() {
+	found := map[string]string{}
+	files, _ := filepath.Glob(filepath.Join(playlistDir(), "*.m3u"))
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+		if _, clash := cfg.findSystem(name); clash {
+			logWarn("%s: skipping playlist named like a system", f)
+			continue
+		}
+		found[name] = f
+	}
+	playlistMu.Lock()
+	playlistFiles = found
+	playlistMu.Unlock()
+}
+
+// playlistNames lists the playlists in name order
+func playlistNames() []string {
+	var names []string
+	for name := range playlists() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isPlaylist reports whether system is a playlist rather than a real system
+func isPlaylist(system string) bool {
+	_, ok := playlists()[system]
+	return ok
+}
+
+// loadPlaylist reads an M3U file: one ROM path per line, relative to the
+// playlist, each optionally titled by a preceding "#EXTINF:<secs>,Title".
+// Every entry keeps the system it comes from, so it launches with that core.
+func loadPlaylist(path string) ([]GameEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var games []GameEntry
+	title := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXTINF:"):
+			if i := strings.Index(line, ","); i >= 0 {
+				title = strings.TrimSpace(line[i+1:])
+			}
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		}
+
+		rom := line
+		if !filepath.IsAbs(rom) {
+			rom = filepath.Join(filepath.Dir(path), rom)
+		}
+		if title == "" {
+			base := filepath.Base(rom)
+			title = strings.TrimSuffix(base, filepath.Ext(base))
+		}
+		games = append(games, GameEntry{Title: title, Path: rom, System: originSystem(rom)})
+		title = ""
+	}
+	return games, scanner.Err()
+}
+
+// originSystem works out which system a playlist entry belongs to: the one
+// whose ROM folder holds it, else the one its extension or header suggests
+func originSystem(rom string) string {
+	file := rom
+	if archive, _, ok := splitArchivePath(rom); ok {
+		file = archive
+	}
+	for _, sys := range cfg.Systems {
+		if rel, err := filepath.Rel(sys.romDir(), file); err == nil && !strings.HasPrefix(rel, "..") {
+			return sys.Name
+		}
+	}
+	return classify(rom)
+}
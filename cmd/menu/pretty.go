@@ -0,0 +1,125 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// TitleRules configure how file names are tidied for display, under
+// "titles:" in systems.yaml. Rules run in order; tags containing one of
+// Keep (e.g. "(Disc 1)") survive the bracket and paren rules.
+type TitleRules struct {
+	Rules []string `yaml:"rules,omitempty"`
+	Keep  []string `yaml:"keep,omitempty"`
+}
+
+// defaultTitleRules apply when systems.yaml doesn't list any
+var defaultTitleRules = []string{"underscores", "brackets", "parens", "spaces"}
+
+var (
+	bracketTag = regexp.MustCompile(`\[[^\]]*\]`)
+	parenTag   = regexp.MustCompile(`\([^)]*\)`)
+)
+
+// titleRules are the rewrites that can be listed under titles.rules
+var titleRules = map[string]func(s string, keep []string) string{
+	"underscores": func(s string, _ []string) string {
+		return strings.ReplaceAll(s, "_", " ")
+	},
+	"brackets": func(s string, keep []string) string {
+		return stripTags(bracketTag, s, keep)
+	},
+	"parens": func(s string, keep []string) string {
+		return stripTags(parenTag, s, keep)
+	},
+	"spaces": func(s string, _ []string) string {
+		return strings.Join(strings.Fields(s), " ")
+	},
+}
+
+// prettyTitles remembers tidied names, since sorting asks for them often
+var prettyTitles sync.Map
+
+// prettyTitle tidies a ROM file name for display, so
+// "Super_Mario_Bros_(U)_[!].nes" reads "Super Mario Bros". The raw name is
+// still what gets launched.
+func prettyTitle(filename string) string {
+	if v, ok := prettyTitles.Load(filename); ok {
+		return v.(string)
+	}
+
+	title := filename
+	if ext := filepath.Ext(title); ext != "" {
+		if _, known := systemForExtension(ext); known || strings.EqualFold(ext, ".zip") {
+			title = strings.TrimSuffix(title, ext)
+		}
+	}
+	var rules TitleRules
+	if cfg != nil {
+		rules = cfg.Titles
+	}
+	names := rules.Rules
+	if len(names) == 0 {
+		names = defaultTitleRules
+	}
+	for _, name := range names {
+		if rule, ok := titleRules[name]; ok {
+			title = rule(title, rules.Keep)
+		}
+	}
+	if title = strings.TrimSpace(title); title == "" {
+		title = filename
+	}
+
+	prettyTitles.Store(filename, title)
+	return title
+}
+
+// stripTags removes the matches of tag from s, except those containing one
+// of keep
+func stripTags(tag *regexp.Regexp, s string, keep []string) string {
+	return tag.ReplaceAllStringFunc(s, func(m string) string {
+		for _, k := range keep {
+			if strings.Contains(strings.ToLower(m), strings.ToLower(k)) {
+				return m
+			}
+		}
+		return " "
+	})
+}
+
+// displayTitle is how a game is shown. Scanned games are titled after their
+// file, so they are tidied; titles written in systems.yaml are left alone.
+func displayTitle(game GameEntry) string {
+	if game.Path == "" || !titledAfterFile(game) {
+		return game.Title
+	}
+	return prettyTitle(game.Title)
+}
+
+// titledAfterFile reports whether a game's title is its ROM's (or its
+// archive's) file name
+func titledAfterFile(game GameEntry) bool {
+	paths := []string{game.Path}
+	if archive, _, ok := splitArchivePath(game.Path); ok {
+		paths = append(paths, archive)
+	}
+	for _, p := range paths {
+		base := filepath.Base(p)
+		if game.Title == strings.TrimSuffix(base, filepath.Ext(base)) {
+			return true
+		}
+	}
+	return false
+}
+
+// rowTitle is the text shown for a game row; grouped variants show their
+// shared base title
+func rowTitle(row gameRow) string {
+	if row.variants != nil {
+		return prettyTitle(baseTitle(row.game.Title))
+	}
+	return displayTitle(row.game)
+}
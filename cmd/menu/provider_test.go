@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// memoryProvider is a GameProvider over fixed lists, so the UI can be
+// driven with no ROM folders at all
+type memoryProvider struct {
+	systems []SystemConfig
+	games   map[string][]GameEntry
+}
+
+func (p memoryProvider) Systems() []SystemConfig {
+	return p.systems
+}
+
+func (p memoryProvider) Games(system string) ([]GameEntry, error) {
+	games, ok := p.games[system]
+	if !ok {
+		return nil, fmt.Errorf("unknown system %q", system)
+	}
+	return append([]GameEntry(nil), games...), nil
+}
+
+func (memoryProvider) Cached(string) bool {
+	return true
+}
+
+func (memoryProvider) Unavailable(SystemConfig) string {
+	return ""
+}
+
+// TestGamesFromProvider checks the game list comes from the provider alone:
+// the system's folder doesn't exist and its games aren't on disk
+func TestGamesFromProvider(t *testing.T) {
+	old := provider
+	t.Cleanup(func() { provider = old })
+	sys := SystemConfig{Name: "Memory", Dir: "/nonexistent"}
+	provider = memoryProvider{
+		systems: []SystemConfig{sys},
+		games: map[string][]GameEntry{"Memory": {
+			{Title: "Zelda", System: "Memory", Path: "/nonexistent/Zelda.nes"},
+			{Title: "Metroid", System: "Memory", Path: "/nonexistent/Metroid.nes"},
+		}},
+	}
+	startTestUI(t)
+	// The startup warm reads cfg, so let it finish before adding to it
+	ui.wait()
+	tryUpdate(t, func() {
+		cfg.Systems = append(cfg.Systems, sys)
+		rebuildSystemList()
+	})
+
+	loaded := make(chan struct{})
+	tryUpdate(t, func() {
+		if !systemAvailable("Memory") {
+			t.Error("Memory is unavailable, though the provider lists it")
+		}
+		loadGamesThen("Memory", func() { close(loaded) })
+	})
+	<-loaded
+	var titles []string
+	tryUpdate(t, func() {
+		for _, r := range ui.shownRows {
+			if r.isGame() {
+				titles = append(titles, r.game.Title)
+			}
+		}
+	})
+	if want := []string{"Metroid", "Zelda"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("Memory lists %q, want %q", titles, want)
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// defaultQueueStep is how long each queued game runs without
+// settings.queue_seconds
+const defaultQueueStep = 5 * time.Minute
+
+// LaunchQueue launches a list of games one after another, each for Step,
+// through the configured launcher. When the last one's time is up, or the
+// queue is stopped, the MiSTer goes back to its menu.
+type LaunchQueue struct {
+	Step time.Duration
+
+	mu      sync.Mutex
+	games   []GameEntry
+	current int // index of the running game, -1 when stopped
+	step    int // counts launches, so a late timer or failure can tell it's stale
+	timer   *time.Timer
+	cancel  context.CancelFunc
+}
+
+// queue is the menu's launch queue
+var queue = &LaunchQueue{Step: defaultQueueStep, current: -1}
+
+// errQueueEmpty is returned when starting a queue with nothing in it
+var errQueueEmpty = errors.New("the launch queue is empty")
+
+// Enqueue adds game to the end of the queue
+func (q *LaunchQueue) Enqueue(game GameEntry) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.games = append(q.games, game)
+}
+
+// Games returns the queued games in order
+func (q *LaunchQueue) Games() []GameEntry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]GameEntry(nil), q.games...)
+}
+
+// Current is the index of the running game, or -1 when stopped
+func (q *LaunchQueue) Current() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.current
+}
+
+// Remove drops game i from the queue. Removing the running game moves
+// straight on to the next one. It runs on the UI goroutine, so the caller
+// refreshes the queue list.
+func (q *LaunchQueue) Remove(i int) {
+	q.mu.Lock()
+	if i < 0 || i >= len(q.games) {
+		q.mu.Unlock()
+		return
+	}
+	q.games = append(q.games[:i], q.games[i+1:]...)
+	if q.current >= 0 && i <= q.current {
+		q.current--
+		if i == q.current+1 {
+			q.advanceLocked()
+			return
+		}
+	}
+	q.mu.Unlock()
+}
+
+// Move swaps game i with its neighbour by (-1 or 1), keeping track of the
+// running game; it reports whether anything moved
+func (q *LaunchQueue) Move(i, by int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j := i + by
+	if i < 0 || j < 0 || i >= len(q.games) || j >= len(q.games) {
+		return false
+	}
+	q.games[i], q.games[j] = q.games[j], q.games[i]
+	switch q.current {
+	case i:
+		q.current = j
+	case j:
+		q.current = i
+	}
+	return true
+}
+
+// Start launches the first queued game and the rest after it in turn
+func (q *LaunchQueue) Start() error {
+	q.mu.Lock()
+	if len(q.games) == 0 {
+		q.mu.Unlock()
+		return errQueueEmpty
+	}
+	q.stopTimer()
+	q.current = -1
+	q.advanceLocked()
+	return nil
+}
+
+// Stop ends the queue and returns the MiSTer to its menu
+func (q *LaunchQueue) Stop() {
+	q.mu.Lock()
+	was := q.current
+	q.stopTimer()
+	q.current = -1
+	q.mu.Unlock()
+	if was >= 0 {
+		ui.spawn(returnToMenu)
+	}
+}
+
+// stopTimer cancels the pending step and any launch in flight; q.mu is held
+func (q *LaunchQueue) stopTimer() {
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+	if q.cancel != nil {
+		q.cancel()
+		q.cancel = nil
+	}
+}
+
+// advanceFrom moves on from the game launched as step, unless the queue has
+// already moved on or stopped since. It runs off the UI goroutine, from the
+// step timer or a failed launch.
+func (q *LaunchQueue) advanceFrom(step int) {
+	q.mu.Lock()
+	if q.step != step || q.current < 0 {
+		q.mu.Unlock()
+		return
+	}
+	if q.advanceLocked() {
+		ui.update(refreshQueueView)
+	}
+}
+
+// advanceLocked launches the game after the running one and schedules the
+// next step, or stops once the queue has run out, reporting whether it did
+// so the open queue list can be refreshed. q.mu is held on entry and
+// unlocked before it returns.
+func (q *LaunchQueue) advanceLocked() (ended bool) {
+	q.stopTimer()
+	q.current++
+	q.step++
+	if q.current >= len(q.games) {
+		q.current = -1
+		q.mu.Unlock()
+		ui.spawn(returnToMenu)
+		return true
+	}
+	game, step := q.games[q.current], q.step
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+	q.timer = time.AfterFunc(q.Step, func() { q.advanceFrom(step) })
+	q.mu.Unlock()
+
+	ui.spawn(func() {
+		cmd, err := launchGameContext(ctx, game)
+		if ctx.Err() != nil {
+			return // stopped or moved on meanwhile
+		}
+		if err != nil {
+			// Skip to the next game rather than idle for a whole step
+			logError("queue: launching %s (%s): %v", game.Title, game.System, err)
+			q.advanceFrom(step)
+			return
+		}
+		ui.update(func() {
+			gameLaunched(game, cmd)
+			refreshQueueView()
+		})
+	})
+	return false
+}
+
+// returnToMenu loads the MiSTer's menu core through the launcher
+func returnToMenu() {
+	menu := GameEntry{Title: "Menu", Command: "load_core " + filepath.Join(misterRoot, "menu.rbf")}
+	if err := launcher.Launch(menu); err != nil {
+		logWarn("returning to the menu: %v", err)
+	}
+}
+
+// queueKey adds the highlighted game to the launch queue on the queue key
+// ('Q')
+func queueKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Queue.Matches(event) {
+		return event
+	}
+	if game, ok := highlightedGame(); ok && game.System != scriptsSystem {
+		queue.Enqueue(game)
+		flashStatus(fmt.Sprintf(T("status.queued"), len(queue.Games())))
+	}
+	return nil
+}
+
+// queueListKey opens the launch queue on the queue list key ('L')
+func queueListKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.QueueList.Matches(event) || !listFocused() {
+		return event
+	}
+	showQueue()
+	return nil
+}
+
+// queueView is the open queue list, so a running queue can update it
+var queueView *tview.List
+
+// showQueue lists the queued games. [ and ] move the highlighted one, d
+// removes it, s starts or stops the queue and Esc closes the list.
+func showQueue() {
+	list := styleList(tview.NewList())
+	queueView = list
+	refreshQueueView()
+	list.SetDoneFunc(func() {
+		queueView = nil
+		nav.pop()
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		i := list.GetCurrentItem()
+		switch event.Rune() {
+		case '[', ']':
+			by := 1
+			if event.Rune() == '[' {
+				by = -1
+			}
+			if queue.Move(i, by) {
+				refreshQueueView()
+				list.SetCurrentItem(i + by)
+			}
+		case 'd':
+			queue.Remove(i)
+			refreshQueueView()
+		case 's':
+			if queue.Current() >= 0 {
+				queue.Stop()
+			} else if err := queue.Start(); err != nil {
+				showMessage(app, err.Error())
+			}
+			refreshQueueView()
+		default:
+			return event
+		}
+		return nil
+	})
+	list.SetBorder(true)
+	nav.push(list)
+}
+
+// refreshQueueView redraws the open queue list, if there is one
+func refreshQueueView() {
+	list := queueView
+	if list == nil {
+		return
+	}
+	i := list.GetCurrentItem()
+	list.Clear()
+	games, current := queue.Games(), queue.Current()
+	for n, g := range games {
+		marker := "  "
+		if n == current {
+			marker = "▶ "
+		}
+		list.AddItem(marker+tview.Escape(displayTitle(g)), "  "+tview.Escape(g.System), 0, nil)
+	}
+	if len(games) == 0 {
+		addPlaceholderItem(list, "(nothing queued)")
+	}
+	list.SetCurrentItem(i)
+
+	state := "stopped"
+	if current >= 0 {
+		state = fmt.Sprintf("running, %s each", queue.Step)
+	}
+	list.SetTitle(" " + strings.Join([]string{"Launch queue (" + state + ")", "[ ] move", "d remove", "s start/stop", "Esc close"}, ", ") + " ")
+}
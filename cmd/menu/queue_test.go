@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestRemoveLastRunningQueueEntry(t *testing.T) {
+	rec := &recordingLauncher{}
+	old, oldQueue := launcher, queue
+	launcher = rec
+	queue = &LaunchQueue{Step: time.Hour, current: -1}
+	t.Cleanup(func() { launcher, queue, queueView = old, oldQueue, nil })
+	screen := startTestUI(t, "NES/Zelda.nes")
+
+	queue.Enqueue(GameEntry{Title: "Zelda", System: "NES", Path: "/nes/Zelda.nes"})
+	tryUpdate(t, func() {
+		if err := queue.Start(); err != nil {
+			t.Fatal(err)
+		}
+		showQueue()
+	})
+
+	// The running game is the last, so removing it ends the queue
+	screen.InjectKey(tcell.KeyRune, 'd', tcell.ModNone)
+	waitFor(t, "the queue to stop", func() bool {
+		return queue.Current() < 0 && len(queue.Games()) == 0
+	})
+	tryUpdate(t, func() {
+		if n := queueView.GetItemCount(); n != 1 {
+			t.Errorf("queue list has %d rows, want just the placeholder", n)
+		}
+	})
+}
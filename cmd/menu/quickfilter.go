@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// What unbound letter keys do in the game list, from settings.letter_keys
+const (
+	letterKeysJump   = "jump"
+	letterKeysFilter = "filter"
+)
+
+var (
+	// letterKeys is letterKeysJump (type-ahead) or letterKeysFilter
+	letterKeys = letterKeysJump
+
+	// letterFilter limits the game list to titles filed under this index
+	// letter ('#' for the rest); 0 shows everything
+	letterFilter byte
+)
+
+// filteredByLetter returns the games whose titles start with letterFilter,
+// in their current order
+func filteredByLetter(games []GameEntry) []GameEntry {
+	if letterFilter == 0 {
+		return games
+	}
+	var out []GameEntry
+	for _, g := range games {
+		if titleLetter(displayTitle(g)) == letterFilter {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// letterFilterKey filters the game list to the letter pressed, when letter
+// keys are set to filter
+func letterFilterKey(event *tcell.EventKey) *tcell.EventKey {
+	r := event.Rune()
+	if event.Key() != tcell.KeyRune || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+		return event
+	}
+	letter := titleLetter(string(r))
+	if letter == letterFilter {
+		return nil
+	}
+	letterFilter = letter
+	filterGames(searchBox.GetText())
+	if len(ui.shownRows) > 0 {
+		pagedGames.Select(0)
+	}
+	return nil
+}
+
+// clearLetterFilterKey drops the letter filter on Esc before Esc does
+// anything else
+func clearLetterFilterKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyEscape || letterFilter == 0 {
+		return event
+	}
+	letterFilter = 0
+	filterGames(searchBox.GetText())
+	return nil
+}
+
+// letterFilterText is the status bar note for an active letter filter
+func letterFilterText() string {
+	if letterFilter == 0 {
+		return ""
+	}
+	return "  [yellow]" + fmt.Sprintf(T("status.letter_only"), string(letterFilter)) + "[-]"
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// quitOpen is set while the quit confirmation is on screen
+var quitOpen bool
+
+// globalKeys intercepts the keys that work from anywhere before any widget
+// sees them
+func globalKeys(event *tcell.EventKey) *tcell.EventKey {
+	if event = wakeKey(event); event == nil {
+		return nil
+	}
+	if event = consoleToggleKey(event); event == nil {
+		return nil
+	}
+	event = backKey(event)
+	if event.Key() == tcell.KeyRune && app.GetFocus() == gameList && typeAheadActive() {
+		return event
+	}
+	if keys.Random.Matches(event) && listFocused() {
+		launchRandom()
+		return nil
+	}
+	if keys.SearchAll.Matches(event) && listFocused() {
+		startGlobalSearch()
+		return nil
+	}
+	if keys.History.Matches(event) && listFocused() {
+		showHistory(app)
+		return nil
+	}
+	if event = helpKey(event); event == nil {
+		return nil
+	}
+
+	switch {
+	case event.Key() == tcell.KeyCtrlC:
+	case keys.Quit.Matches(event):
+		if keys.Quit.Key == tcell.KeyRune && app.GetFocus() == searchBox {
+			return event
+		}
+	default:
+		return event
+	}
+	if !quitOpen {
+		confirmQuit()
+	}
+	return nil
+}
+
+// confirmQuit asks before stopping the application
+func confirmQuit() {
+	quitOpen = true
+	modal := tview.NewModal().
+		SetText(T("msg.quit")).
+		AddButtons([]string{T("button.yes"), T("button.no")}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			quitOpen = false
+			if buttonIndex == 0 {
+				app.Stop()
+				return
+			}
+			nav.pop()
+		})
+	nav.push(modal)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// randomDelay is how long the chosen game is shown before it launches
+const randomDelay = time.Second
+
+var (
+	// rng is shared by the UI goroutine and background picks, so it is
+	// only used under rngMu
+	rng   = rand.New(rand.NewSource(time.Now().UnixNano()))
+	rngMu sync.Mutex
+)
+
+// randIntn returns a random number in [0,n)
+func randIntn(n int) int {
+	rngMu.Lock()
+	defer rngMu.Unlock()
+	return rng.Intn(n)
+}
+
+// pickRandom chooses a game from system, or from every system when empty.
+// Systems not yet cached are scanned, so it is called off the UI goroutine
+// unless provider.Cached says otherwise.
+func pickRandom(system string) (GameEntry, bool) {
+	var pool []GameEntry
+	switch system {
+	case recentSystem:
+		pool = recentGames
+	case favoritesSystem:
+		pool = favoriteGames
+	case mostPlayedSystem:
+		pool = topPlayed(maxMostPlayed)
+	case "":
+		for _, sys := range provider.Systems() {
+			games, _ := provider.Games(sys.Name)
+			pool = append(pool, games...)
+		}
+	default:
+		pool, _ = provider.Games(system)
+	}
+	if len(pool) == 0 {
+		return GameEntry{}, false
+	}
+	return pool[randIntn(len(pool))], true
+}
+
+// randomSystem picks a system for a game from anywhere, weighted by the
+// counts shown so far so every game is as likely as any other. It returns
+// "" before anything has been counted.
+func randomSystem() string {
+	total := 0
+	for _, sys := range provider.Systems() {
+		total += ui.systemCounts[sys.Name]
+	}
+	if total == 0 {
+		return ""
+	}
+	n := randIntn(total)
+	for _, sys := range provider.Systems() {
+		if n -= ui.systemCounts[sys.Name]; n < 0 {
+			return sys.Name
+		}
+	}
+	return ""
+}
+
+// launchRandom shows a random pick for a moment and then launches it. With
+// no system open the system is picked first, so only it may need a scan,
+// and any scan runs in the background.
+func launchRandom() {
+	system := currentSystem
+	if system == "" {
+		system = randomSystem()
+	}
+	if system != "" && provider.Cached(system) {
+		showRandomPick(pickRandom(system))
+		return
+	}
+	ui.spawn(func() {
+		game, ok := pickRandom(system)
+		ui.update(func() { showRandomPick(game, ok) })
+	})
+}
+
+// showRandomPick shows game for randomDelay and then launches it
+func showRandomPick(game GameEntry, ok bool) {
+	if !ok {
+		showMessage(app, T("msg.nothing_to_pick"))
+		return
+	}
+
+	modal := tview.NewModal().SetText("Random pick:\n\n" + game.Title + " (" + game.System + ")")
+	nav.push(modal)
+	time.AfterFunc(randomDelay, func() {
+		ui.update(func() {
+			nav.close(modal)
+			startGame(game)
+		})
+	})
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+// TestRandomFromEverywhere launches a random game with no system open, both
+// once the counts are in and before anything has been scanned
+func TestRandomFromEverywhere(t *testing.T) {
+	rec := &recordingLauncher{}
+	old := launcher
+	launcher = rec
+	t.Cleanup(func() { launcher = old })
+	startTestUI(t, "SNES/Mario.sfc")
+	ui.wait()
+
+	tryUpdate(t, func() {
+		if got := randomSystem(); got != "SNES" {
+			t.Errorf("randomSystem() = %q, want the only system with games", got)
+		}
+	})
+
+	for i, uncounted := range []bool{false, true} {
+		tryUpdate(t, func() {
+			if uncounted {
+				ui.systemCounts = map[string]int{}
+				romCacheMu.Lock()
+				romCache = map[string][]GameEntry{}
+				romCacheMu.Unlock()
+			}
+			launchRandom()
+		})
+		waitFor(t, "the random pick to launch", func() bool { return len(rec.games()) == i+1 })
+		if got := rec.games()[i].Title; got != "Mario" {
+			t.Errorf("uncounted %v: launched %q, want Mario", uncounted, got)
+		}
+	}
+}
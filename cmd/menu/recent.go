@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	recentSystem = "Recent"
+	maxRecent    = 20
+)
+
+// recentGames is the launch history, most recent first
+var recentGames []GameEntry
+
+// recentPath is the file the recent list is saved to
+func recentPath() string {
+	return filepath.Join(configDir(), "recent.json")
+}
+
+// loadRecent reads the saved recent list, if there is one
+func loadRecent() {
+	loadJSON(recentPath(), &recentGames)
+}
+
+// recordRecent moves entry to the top of the recent list and saves it
+func recordRecent(entry GameEntry) {
+	games := []GameEntry{entry}
+	for _, g := range recentGames {
+		if !sameGame(g, entry) && len(games) < maxRecent {
+			games = append(games, g)
+		}
+	}
+	saveRecent(games)
+
+	if currentSystem == recentSystem {
+		loadGames(app, recentSystem)
+	}
+}
+
+// removeRecent drops entry from the recent list and saves it
+func removeRecent(entry GameEntry) {
+	games := make([]GameEntry, 0, len(recentGames))
+	for _, g := range recentGames {
+		if !sameGame(g, entry) {
+			games = append(games, g)
+		}
+	}
+	saveRecent(games)
+}
+
+// clearRecent empties the recent list and saves it
+func clearRecent() {
+	saveRecent(nil)
+}
+
+// saveRecent replaces the recent list, saves it and updates its count
+func saveRecent(games []GameEntry) {
+	recentGames = games
+	if err := saveJSON(recentPath(), recentGames); err != nil {
+		reportSaveError("recent games", err)
+	}
+	setSystemCount(recentSystem, len(recentGames))
+}
+
+// filterRecent returns the recent games whose title matches query, still
+// most recent first rather than by how well they match
+func filterRecent(query string) []GameEntry {
+	if strings.TrimSpace(query) == "" {
+		return append([]GameEntry(nil), recentGames...)
+	}
+	var games []GameEntry
+	for _, g := range recentGames {
+		if fuzzyScore(query, displayTitle(g)) >= 0 {
+			games = append(games, g)
+		}
+	}
+	return games
+}
+
+// recentKeys removes the highlighted game from the recent list on the
+// remove key ('d') and offers to clear the whole list on the clear key
+// ('X'). Elsewhere both keys pass through.
+func recentKeys(event *tcell.EventKey) *tcell.EventKey {
+	if currentSystem != recentSystem {
+		return event
+	}
+	switch {
+	case keys.Remove.Matches(event):
+		game, ok := highlightedGame()
+		if !ok {
+			return nil
+		}
+		i := gameList.GetCurrentItem()
+		removeRecent(game)
+		reloadRecent(i)
+	case keys.Clear.Matches(event):
+		if len(recentGames) > 0 {
+			confirmClearRecent()
+		}
+	default:
+		return event
+	}
+	return nil
+}
+
+// reloadRecent lists the recent games again with the cursor on row i, or
+// the last row if the list got shorter
+func reloadRecent(i int) {
+	ui.gameCache = append([]GameEntry(nil), recentGames...)
+	filterGames(searchBox.GetText())
+	if n := len(ui.shownRows); n > 0 {
+		if i >= n {
+			i = n - 1
+		}
+		pagedGames.Select(i)
+	}
+}
+
+// confirmClearRecent asks before forgetting every recent game
+func confirmClearRecent() {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf(T("msg.clear_recent"), len(recentGames))).
+		AddButtons([]string{T("button.clear"), T("button.cancel")}).
+		SetDoneFunc(func(i int, _ string) {
+			nav.pop()
+			if i == 0 {
+				clearRecent()
+				reloadRecent(0)
+			}
+		})
+	nav.push(modal)
+}
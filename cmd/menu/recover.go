@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// guarded wraps the root primitive so a panic in any handler below it, such
+// as a list's selected func, is logged and shown instead of ending the menu
+type guarded struct {
+	tview.Primitive
+}
+
+// setRoot shows p full screen behind the panic guard
+func setRoot(p tview.Primitive) {
+	app.SetRoot(guarded{p}, true)
+}
+
+// Draw draws the wrapped primitive
+func (g guarded) Draw(screen tcell.Screen) {
+	defer recoverCallback()
+	g.Primitive.Draw(screen)
+}
+
+// InputHandler passes keys to the wrapped primitive
+func (g guarded) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	handler := g.Primitive.InputHandler()
+	if handler == nil {
+		return nil
+	}
+	return func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		defer recoverCallback()
+		handler(event, setFocus)
+	}
+}
+
+// MouseHandler passes mouse events to the wrapped primitive
+func (g guarded) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+	handler := g.Primitive.MouseHandler()
+	if handler == nil {
+		return nil
+	}
+	return func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		defer recoverCallback()
+		return handler(action, event, setFocus)
+	}
+}
+
+// PasteHandler passes pasted text to the wrapped primitive
+func (g guarded) PasteHandler() func(text string, setFocus func(p tview.Primitive)) {
+	handler := g.Primitive.PasteHandler()
+	if handler == nil {
+		return nil
+	}
+	return func(text string, setFocus func(p tview.Primitive)) {
+		defer recoverCallback()
+		handler(text, setFocus)
+	}
+}
+
+// guardedCapture wraps an application input capture, which runs before the
+// root primitive's guard, so a panic in it is caught too and the key dropped
+func guardedCapture(capture func(event *tcell.EventKey) *tcell.EventKey) func(event *tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		defer recoverCallback()
+		return capture(event)
+	}
+}
+
+// recoverCallback, deferred in a handler, turns a panic into a logged stack
+// and an error modal. The modal is queued since the panic may be mid-draw.
+func recoverCallback() {
+	p := recover()
+	if p == nil {
+		return
+	}
+	logError("panic: %v\n%s", p, debug.Stack())
+	go ui.update(func() {
+		showMessageFor(app, fmt.Sprintf("Something went wrong: %v\n\nThe menu is still running; details are in the log.", p), 0)
+	})
+}
+
+// recoverMain, deferred in main, catches a panic that escaped the guards:
+// the screen is put back first so the trace is readable and the terminal
+// usable
+func recoverMain() {
+	p := recover()
+	if p == nil {
+		return
+	}
+	if app != nil {
+		app.Stop()
+	}
+	stack := debug.Stack()
+	logError("panic: %v\n%s", p, stack)
+	fmt.Fprintf(os.Stderr, "panic: %v\n\n%s", p, stack)
+	os.Exit(2)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// TestPanicsAreCaught checks a panic in a queued update or in the global key
+// capture is reported in a modal while the menu keeps running
+func TestPanicsAreCaught(t *testing.T) {
+	startTestUI(t)
+	closeAll := func() {
+		for !nav.empty() {
+			nav.pop()
+		}
+	}
+
+	ui.update(func() { panic("boom") })
+	waitFor(t, "the panic from an update to be shown", func() bool { return !nav.empty() })
+	tryUpdate(t, closeAll)
+
+	boom := guardedCapture(func(*tcell.EventKey) *tcell.EventKey { panic("boom") })
+	tryUpdate(t, func() {
+		if boom(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone)) != nil {
+			t.Error("a key whose capture panicked went on to the widgets")
+		}
+	})
+	waitFor(t, "the panic from a key to be shown", func() bool { return !nav.empty() })
+	tryUpdate(t, closeAll)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// networkTimeout bounds each network operation, such as a remote launch or
+// answering a control client, so an unreachable peer can't hang anything;
+// set with -timeout
+var networkTimeout = 5 * time.Second
+
+// RemoteLauncher sends launch commands to a MiSTer's command FIFO over SSH
+type RemoteLauncher struct {
+	User string
+	Addr string // host:port
+}
+
+// NewRemoteLauncher parses a "user@host[:port]" target; the user defaults
+// to root and the port to 22
+func NewRemoteLauncher(target string) (*RemoteLauncher, error) {
+	user, host := "root", target
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		user, host = target[:i], target[i+1:]
+	}
+	if host == "" || user == "" {
+		return nil, fmt.Errorf("%q: want user@host", target)
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+	return &RemoteLauncher{User: user, Addr: host}, nil
+}
+
+// Launch loads a game on the remote MiSTer, giving up after networkTimeout
+func (r *RemoteLauncher) Launch(game GameEntry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), networkTimeout)
+	defer cancel()
+	return r.LaunchContext(ctx, game)
+}
+
+// LaunchContext loads a game on the remote MiSTer, giving up when ctx ends
+func (r *RemoteLauncher) LaunchContext(ctx context.Context, game GameEntry) error {
+	return r.send(ctx, launchCommand(game))
+}
+
+// send runs a shell on the MiSTer that writes cmd to its command FIFO
+func (r *RemoteLauncher) send(ctx context.Context, cmd string) error {
+	return r.run(ctx, "echo "+shellQuote(cmd)+" > "+cmdDevice, "writing "+cmdDevice)
+}
+
+// check confirms the MiSTer answers over SSH and has its command FIFO
+func (r *RemoteLauncher) check(ctx context.Context) error {
+	return r.run(ctx, "test -p "+cmdDevice, "looking for "+cmdDevice)
+}
+
+// run connects and runs a shell command on the MiSTer; step names what the
+// command does for errors. Ending ctx closes the connection, which aborts
+// whatever step is running.
+func (r *RemoteLauncher) run(ctx context.Context, shell, step string) error {
+	config, closeAgent, err := r.clientConfig()
+	if err != nil {
+		return err
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.Addr)
+	if err != nil {
+		closeAgent()
+		return r.failed(ctx, "connecting to "+r.Addr, err)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, r.Addr, config)
+	closeAgent()
+	if err != nil {
+		conn.Close()
+		return r.failed(ctx, "connecting to "+r.Addr, err)
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return r.failed(ctx, r.Addr, err)
+	}
+	defer session.Close()
+	if err := session.Run(shell); err != nil {
+		return r.failed(ctx, r.Addr+": "+step, err)
+	}
+	return nil
+}
+
+// failed words an error from step, blaming ctx when it ran out or was
+// cancelled, since the error itself is then just a closed connection
+func (r *RemoteLauncher) failed(ctx context.Context, step string, err error) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return fmt.Errorf("%s: no answer after %s", step, networkTimeout)
+	case context.Canceled:
+		return fmt.Errorf("%s: cancelled", step)
+	}
+	return fmt.Errorf("%s: %w", step, err)
+}
+
+// clientConfig authenticates with the SSH agent, the usual key files or
+// PEEPER_SSH_PASSWORD, and checks the host against ~/.ssh/known_hosts. The
+// returned func closes the agent connection once the handshake is over.
+func (r *RemoteLauncher) clientConfig() (*ssh.ClientConfig, func(), error) {
+	closeAgent := func() {}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, closeAgent, err
+	}
+	hostKeys, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, closeAgent, fmt.Errorf("reading known hosts (ssh to %s once to add it): %w", r.Addr, err)
+	}
+
+	var auth []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			closeAgent = func() { conn.Close() }
+			auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+	var signers []ssh.Signer
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		data, err := os.ReadFile(filepath.Join(home, ".ssh", name))
+		if err != nil {
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			logWarn("ssh key %s: %v", name, err)
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		auth = append(auth, ssh.PublicKeys(signers...))
+	}
+	if pw := os.Getenv("PEEPER_SSH_PASSWORD"); pw != "" {
+		auth = append(auth, ssh.Password(pw))
+	}
+	if len(auth) == 0 {
+		return nil, closeAgent, errors.New("no SSH agent, key or PEEPER_SSH_PASSWORD to log in with")
+	}
+
+	return &ssh.ClientConfig{
+		User:            r.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeys,
+		Timeout:         networkTimeout,
+	}, closeAgent, nil
+}
+
+// shellQuote wraps s in single quotes for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
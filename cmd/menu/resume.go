@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// resumeLast launches the most recently played game without the menu, for
+// -resume; ok is false when there is nothing to resume and the menu should
+// open instead. Scripts are passed over since they need the output window.
+func resumeLast() (code int, ok bool) {
+	var game GameEntry
+	for _, g := range recentGames {
+		if g.System != scriptsSystem {
+			game, ok = g, true
+			break
+		}
+	}
+	if !ok {
+		logInfo("nothing to resume, opening the menu")
+		return 0, false
+	}
+	if d, dry := launcher.(*DryRunLauncher); dry {
+		d.Show = func(msg string) { fmt.Println(msg) }
+	}
+
+	cmd, err := launchGame(game)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resuming %s: %v\n", game.Title, err)
+		return 1, true
+	}
+	logInfo("resumed %s (%s): %q", game.Title, game.System, cmd)
+	recordRecent(game)
+	incrementPlayCount(game)
+	if err := appendHistory(game, time.Now()); err != nil {
+		reportSaveError("history", err)
+	}
+	return 0, true
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// saveStateArg is the launch argument naming the slot to resume, followed
+// by "=" and the slot number
+const saveStateArg = "savestate"
+
+// saveStateExt is the extension MiSTer gives save state files
+const saveStateExt = ".ss"
+
+// SaveState is one save state file found for a game
+type SaveState struct {
+	Slot    int
+	Path    string
+	ModTime time.Time
+}
+
+// saveStatesDir is where MiSTer keeps save states, one folder per core,
+// beside the games folder
+func saveStatesDir() string {
+	return filepath.Join(filepath.Dir(gamesDir), "savestates")
+}
+
+// romName is the file name of game's ROM without its extension, which save
+// states are named after; archived ROMs go by their member name
+func romName(game GameEntry) string {
+	name := filepath.Base(game.Path)
+	if _, member, ok := splitArchivePath(game.Path); ok {
+		name = path.Base(member)
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// saveStatesFor lists the save states of game's ROM in its core's save
+// folder, named "<rom>_<slot>.ss", lowest slot first
+func saveStatesFor(game GameEntry) []SaveState {
+	if game.Path == "" || game.Command != "" {
+		return nil
+	}
+	dir := filepath.Join(saveStatesDir(), gameCore(game))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logWarn("save states: %v", err)
+		}
+		return nil
+	}
+	prefix := romName(game) + "_"
+	var states []SaveState
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.EqualFold(filepath.Ext(name), saveStateExt) {
+			continue
+		}
+		slot, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), filepath.Ext(name)))
+		if err != nil || slot < 0 {
+			continue
+		}
+		s := SaveState{Slot: slot, Path: filepath.Join(dir, name)}
+		if info, err := e.Info(); err == nil {
+			s.ModTime = info.ModTime()
+		}
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Slot < states[j].Slot })
+	return states
+}
+
+// withSaveState is game launched resuming from slot
+func withSaveState(game GameEntry, slot int) GameEntry {
+	game.Args = append(append([]string(nil), game.Args...), saveStateArg+"="+strconv.Itoa(slot))
+	return game
+}
+
+// pickSaveState offers game's save states before it starts, calling then
+// with the game as chosen; without states, or with -fast, it starts fresh
+func pickSaveState(game GameEntry, then func(GameEntry)) {
+	states := saveStatesFor(game)
+	if len(states) == 0 || fastMode {
+		then(game)
+		return
+	}
+	labels := []string{"Fresh start"}
+	for _, s := range states {
+		label := "Resume from slot " + strconv.Itoa(s.Slot)
+		if !s.ModTime.IsZero() {
+			label += "  " + s.ModTime.Local().Format("2006-01-02 15:04")
+		}
+		labels = append(labels, label)
+	}
+	showPicker("Start "+displayTitle(game), labels, 0, func(i int) {
+		if i > 0 {
+			game = withSaveState(game, states[i-1].Slot)
+		}
+		then(game)
+	})
+}
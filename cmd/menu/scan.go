@@ -0,0 +1,268 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// romExtensions lists the file types each system's core can load
+var romExtensions = map[string][]string{
+	"NES":     {".nes", ".fds", ".nsf"},
+	"SNES":    {".sfc", ".smc", ".bs"},
+	"Genesis": {".md", ".gen", ".bin", ".smd"},
+	"PSX":     {".chd", ".cue"},
+	"Saturn":  {".chd", ".cue"},
+	"MegaCD":  {".chd", ".cue"},
+}
+
+// extensionsFor returns the ROM extensions for a system, preferring the
+// list from systems.yaml over the built-in one
+func extensionsFor(system string) []string {
+	if sys, ok := cfg.findSystem(system); ok && len(sys.Extensions) > 0 {
+		return sys.Extensions
+	}
+	return romExtensions[system]
+}
+
+// matchesFilters reports whether a file called name passes a system's
+// include and exclude globs, compared without regard to case. Excludes win
+// over includes, and with no includes every name not excluded passes.
+func matchesFilters(name string, inc, exc []string) bool {
+	name = strings.ToLower(name)
+	for _, g := range exc {
+		if ok, _ := path.Match(strings.ToLower(g), name); ok {
+			return false
+		}
+	}
+	if len(inc) == 0 {
+		return true
+	}
+	for _, g := range inc {
+		if ok, _ := path.Match(strings.ToLower(g), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// scanRoms lists the ROM files in a system's directory and its subfolders,
+// including ROMs stored inside zip archives
+func scanRoms(system string) ([]GameEntry, error) {
+	return scanRomsContext(context.Background(), system, nil)
+}
+
+// scanRomsContext is scanRoms that stops early when ctx is cancelled and
+// reports the running number of games found to progress, if set
+func scanRomsContext(ctx context.Context, system string, progress func(found int)) ([]GameEntry, error) {
+	sys, _ := cfg.findSystem(system)
+	if sys.Name == "" {
+		sys.Name = system
+	}
+	dir := sys.romDir()
+
+	var games []GameEntry
+	add := func(full string, info fs.FileInfo) {
+		modTime := info.ModTime()
+		folder := relFolder(dir, full)
+
+		ext := filepath.Ext(info.Name())
+		if strings.EqualFold(ext, ".zip") {
+			// Archives are only excluded by name; their members are filtered
+			if !matchesFilters(info.Name(), nil, sys.Exclude) {
+				return
+			}
+			inner, err := scanZip(system, full, modTime)
+			if err != nil {
+				logWarn("skipping %s: %v", full, err)
+			}
+			for i := range inner {
+				inner[i].Folder = folder
+			}
+			games = append(games, inner...)
+			return
+		}
+		if !matchesFilters(info.Name(), sys.Include, sys.Exclude) {
+			return
+		}
+		if !hasExtension(system, ext) {
+			// Misnamed ROMs are recognised by their header instead, unless
+			// the extension belongs to another system
+			if _, claimed := systemForExtension(ext); claimed || sniffSystem(full) != system {
+				return
+			}
+		}
+		games = append(games, GameEntry{
+			Title:   strings.TrimSuffix(info.Name(), ext),
+			Path:    full,
+			System:  system,
+			Folder:  folder,
+			ModTime: modTime,
+		})
+	}
+	// Progress is reported after each file, so the count includes it
+	w := newRomWalker(ctx, func(full string, info fs.FileInfo) {
+		add(full, info)
+		if progress != nil {
+			progress(len(games))
+		}
+	})
+	if err := w.walk(dir, true); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// fileID identifies a file by device and inode, however it was reached
+type fileID struct {
+	dev, ino uint64
+}
+
+// romWalker walks a ROM folder and its subfolders, following symlinks.
+// Directories are entered once per resolved path, so symlink loops end, and
+// files reached through several links are visited once. Anything that can't
+// be read is logged and skipped rather than ending the scan.
+type romWalker struct {
+	ctx   context.Context
+	dirs  map[string]bool
+	files map[fileID]bool
+	visit func(full string, info fs.FileInfo)
+}
+
+// newRomWalker makes a walker calling visit for each readable file
+func newRomWalker(ctx context.Context, visit func(full string, info fs.FileInfo)) *romWalker {
+	return &romWalker{ctx: ctx, dirs: map[string]bool{}, files: map[fileID]bool{}, visit: visit}
+}
+
+// walk visits the files under dir. Only a problem with the top folder
+// itself, or a cancelled ctx, is returned.
+func (w *romWalker) walk(dir string, top bool) error {
+	real, err := filepath.EvalSymlinks(dir)
+	if err == nil {
+		real, err = filepath.Abs(real)
+	}
+	if err != nil {
+		if top {
+			return err
+		}
+		logWarn("skipping %s: %v", dir, err)
+		return nil
+	}
+	if w.dirs[real] {
+		logInfo("skipping %s: already scanned as %s", dir, real)
+		return nil
+	}
+	w.dirs[real] = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if top {
+			return err
+		}
+		logWarn("skipping %s: %v", dir, err)
+		return nil
+	}
+	for _, e := range entries {
+		if err := w.ctx.Err(); err != nil {
+			return err
+		}
+		full := filepath.Join(dir, e.Name())
+		// Stat rather than Lstat, so symlinks count as what they point to
+		info, err := os.Stat(full)
+		if err != nil {
+			logWarn("skipping %s: %v", full, err)
+			continue
+		}
+		if info.IsDir() {
+			if strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			if err := w.walk(full, false); err != nil {
+				return err
+			}
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		if id, ok := identify(info); ok {
+			if w.files[id] {
+				continue
+			}
+			w.files[id] = true
+		}
+		if err := readable(full); err != nil {
+			logWarn("skipping %s: %v", full, err)
+			continue
+		}
+		w.visit(full, info)
+	}
+	return nil
+}
+
+// relFolder is the slash-separated folder of file below root, "" at the top
+func relFolder(root, file string) string {
+	rel, err := filepath.Rel(root, filepath.Dir(file))
+	if err != nil || rel == "." {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// scanZip lists the ROMs inside an archive without extracting it. A lone
+// ROM is titled after the archive; otherwise each ROM gets its own entry.
+func scanZip(system, zipPath string, modTime time.Time) ([]GameEntry, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	sys, _ := cfg.findSystem(system)
+	var games []GameEntry
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		ext := path.Ext(f.Name)
+		if !hasExtension(system, ext) || !matchesFilters(path.Base(f.Name), sys.Include, sys.Exclude) {
+			continue
+		}
+		games = append(games, GameEntry{
+			Title:   strings.TrimSuffix(path.Base(f.Name), ext),
+			Path:    zipPath + "/" + f.Name,
+			System:  system,
+			ModTime: modTime,
+		})
+	}
+	if len(games) == 1 {
+		games[0].Title = strings.TrimSuffix(filepath.Base(zipPath), filepath.Ext(zipPath))
+	}
+	return games, nil
+}
+
+// splitArchivePath splits "dir/set.zip/game.nes" into the archive and the
+// member name; ok is false for plain files
+func splitArchivePath(p string) (archive, member string, ok bool) {
+	lower := strings.ToLower(p)
+	i := strings.Index(lower, ".zip/")
+	if i < 0 {
+		return "", "", false
+	}
+	return p[:i+4], p[i+5:], true
+}
+
+// hasExtension reports whether ext is a known ROM type for the system
+func hasExtension(system, ext string) bool {
+	for _, e := range extensionsFor(system) {
+		if strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
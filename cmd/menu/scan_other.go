@@ -0,0 +1,23 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package main
+
+import (
+	"io/fs"
+	"os"
+)
+
+// identify has no inode to go on here, so files reached through several links
+// are each visited
+func identify(info fs.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
+
+// readable reports why path can't be read, if it can't, by opening it
+func readable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// spinnerFrames animate the scanning modal
+var spinnerFrames = []rune(`|/-\`)
+
+// loadGamesThen shows a system's games and then calls done (if set). Cached
+// and virtual systems load immediately; otherwise the scan runs in the
+// background behind a progress modal, and the list is only replaced once it
+// completes. Cancelling with Esc keeps the previous system on screen.
+func loadGamesThen(system string, done func()) {
+	if provider.Cached(system) {
+		games, err := provider.Games(system)
+		showGames(system, games, err)
+		if done != nil {
+			done()
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var found int64
+	var finished int32
+
+	modal := tview.NewModal()
+	back := func() {
+		nav.close(modal)
+	}
+	modal.SetText(fmt.Sprintf(T("msg.scanning"), system)).
+		AddButtons([]string{T("button.cancel")}).
+		SetDoneFunc(func(int, string) {
+			// Esc or Cancel: drop the scan and keep what was showing
+			if atomic.CompareAndSwapInt32(&finished, 0, 1) {
+				cancel()
+				back()
+			}
+		})
+	nav.push(modal)
+
+	ui.spawn(func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			n := atomic.LoadInt64(&found)
+			text := fmt.Sprintf(T("msg.scan_progress"), system, spinnerFrames[frame%len(spinnerFrames)], n)
+			ui.update(func() {
+				if atomic.LoadInt32(&finished) == 0 {
+					modal.SetText(text)
+				}
+			})
+		}
+	})
+
+	ui.spawn(func() {
+		games, err := scanGames(ctx, system, func(n int) {
+			atomic.StoreInt64(&found, int64(n))
+		})
+		ui.update(func() {
+			if !atomic.CompareAndSwapInt32(&finished, 0, 1) {
+				return // cancelled while the result was queued
+			}
+			cancel()
+			back()
+			showGames(system, games, err)
+			if done != nil {
+				done()
+			}
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// showScreenshots puts a game's newest MiSTer screenshot in the details
+	// pane in place of its cover art, from settings.screenshots
+	showScreenshots bool
+
+	// screenshotsDir is where MiSTer saves screenshots, one folder per core,
+	// from settings.screenshots_dir
+	screenshotsDir = filepath.Join(misterRoot, "screenshots")
+)
+
+// screenshot is one image in a core's screenshot folder
+type screenshot struct {
+	path string
+	game string
+	mod  time.Time
+}
+
+type screenshotListing struct {
+	mod   time.Time
+	shots []screenshot
+}
+
+var (
+	screenshotMu    sync.Mutex
+	screenshotCache = map[string]screenshotListing{}
+)
+
+// latestScreenshot finds the newest screenshot of a game. MiSTer names them
+// <date>_<time>-<rom name>.png in a folder named after the core.
+func latestScreenshot(entry GameEntry) (string, bool) {
+	if entry.Path == "" {
+		return "", false
+	}
+	core := entry.System
+	if sys, ok := cfg.findSystem(entry.System); ok {
+		core = sys.coreName()
+	}
+	base := strings.TrimSuffix(filepath.Base(entry.Path), filepath.Ext(entry.Path))
+
+	var newest screenshot
+	for _, s := range screenshotsIn(filepath.Join(screenshotsDir, core)) {
+		if strings.EqualFold(s.game, base) && s.mod.After(newest.mod) {
+			newest = s
+		}
+	}
+	return newest.path, newest.path != ""
+}
+
+// screenshotsIn lists the images in dir, reading it again only when it
+// has changed
+func screenshotsIn(dir string) []screenshot {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil
+	}
+	screenshotMu.Lock()
+	defer screenshotMu.Unlock()
+	if l, ok := screenshotCache[dir]; ok && l.mod.Equal(info.ModTime()) {
+		return l.shots
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logWarn("reading screenshots: %v", err)
+		return nil
+	}
+	var shots []screenshot
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".png" && ext != ".jpg" && ext != ".jpeg") {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			continue
+		}
+		game := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		if i := strings.IndexByte(game, '-'); i >= 0 {
+			game = game[i+1:]
+		}
+		shots = append(shots, screenshot{filepath.Join(dir, e.Name()), game, fi.ModTime()})
+	}
+	screenshotCache[dir] = screenshotListing{info.ModTime(), shots}
+	return shots
+}
+
+// gameImage is the image the details pane shows for a game: its newest
+// screenshot when they're turned on, otherwise its cover art
+func gameImage(entry GameEntry) string {
+	return imageFor(entry, showScreenshots)
+}
+
+// imageFor is gameImage with screenshots on or off, for callers off the UI
+// goroutine
+func imageFor(entry GameEntry, screenshots bool) string {
+	if screenshots {
+		if path, ok := latestScreenshot(entry); ok {
+			return path
+		}
+	}
+	return coverFor(entry)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// scriptsSystem lists the MiSTer's shell scripts, which run here instead of
+// loading a core
+const scriptsSystem = "Scripts"
+
+// scriptsDir is where MiSTer scripts (update_all.sh and friends) live
+var scriptsDir = filepath.Join(misterRoot, "Scripts")
+
+// scriptLauncher runs scripts picked from the Scripts system
+var scriptLauncher = &ScriptLauncher{Shell: "bash"}
+
+// hasScripts reports whether there is a scripts folder to list
+func hasScripts() bool {
+	info, err := os.Stat(scriptsDir)
+	return err == nil && info.IsDir()
+}
+
+// scriptGames lists the scripts as games, by name
+func scriptGames() ([]GameEntry, error) {
+	matches, err := filepath.Glob(filepath.Join(scriptsDir, "*.sh"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	games := make([]GameEntry, 0, len(matches))
+	for _, m := range matches {
+		games = append(games, GameEntry{
+			Title:  strings.TrimSuffix(filepath.Base(m), ".sh"),
+			Path:   m,
+			System: scriptsSystem,
+		})
+	}
+	return games, nil
+}
+
+// ScriptLauncher runs a script with Shell, showing its output as it comes
+type ScriptLauncher struct {
+	Shell string
+}
+
+// Launch starts the script and opens its output window; only a failure to
+// start is returned, since the exit status is shown in the window
+func (s *ScriptLauncher) Launch(game GameEntry) error {
+	cmd := exec.Command(s.Shell, game.Path)
+	cmd.Dir = filepath.Dir(game.Path)
+
+	output := tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
+	output.SetChangedFunc(func() { app.Draw() })
+	output.SetBorder(true).SetTitle(" " + tview.Escape(game.Title) + " (running) ")
+	w := tview.ANSIWriter(output)
+	cmd.Stdout, cmd.Stderr = w, w
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	logInfo("running script %s", game.Path)
+
+	// Closing is only allowed once the script has finished
+	var finished int32
+	output.SetDoneFunc(func(tcell.Key) {
+		if atomic.LoadInt32(&finished) == 1 {
+			nav.pop()
+		}
+	})
+	nav.push(output)
+
+	ui.spawn(func() {
+		status := "finished"
+		if err := cmd.Wait(); err != nil {
+			status = err.Error()
+			logWarn("script %s: %v", game.Path, err)
+		}
+		ui.update(func() {
+			atomic.StoreInt32(&finished, 1)
+			output.SetTitle(" " + tview.Escape(game.Title) + " (" + tview.Escape(status) + ", Esc to close) ")
+		})
+	})
+	return nil
+}
+
+// runScript runs a script from the Scripts system; a dry run only shows
+// what would run
+func runScript(game GameEntry) {
+	var l Launcher = scriptLauncher
+	if _, dry := launcher.(*DryRunLauncher); dry {
+		l = launcher
+	}
+	if err := l.Launch(game); err != nil {
+		showError(app, fmt.Sprintf(T("error.run"), game.Title), err)
+	}
+}
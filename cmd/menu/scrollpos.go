@@ -0,0 +1,79 @@
+package main
+
+import "strings"
+
+var (
+	// rememberScroll returns to the row each system was left on, from
+	// settings.remember_scroll
+	rememberScroll bool
+
+	// scrollPositions is the unfiltered game list row each system was left
+	// on
+	scrollPositions = map[string]int{}
+
+	// listFiltered is set while a search or letter filter narrows the list;
+	// filterFrom is the row highlighted before it did
+	listFiltered bool
+	filterFrom   = -1
+)
+
+// saveScroll remembers the current system's row before another system's
+// games replace it. While filtered that's the row the filter started from,
+// as filtered rows mean nothing once the filter is gone.
+func saveScroll() {
+	if !rememberScroll || currentSystem == "" {
+		return
+	}
+	i := gameList.GetCurrentItem()
+	if listFiltered {
+		i = filterFrom
+	}
+	if i >= 0 {
+		scrollPositions[currentSystem] = i
+	}
+	listFiltered, filterFrom = false, -1
+}
+
+// restoreScroll goes back to the row system was left on. With a filter
+// active it is restored when the filter is cleared instead.
+func restoreScroll(system string) {
+	i, ok := scrollPositions[system]
+	if !rememberScroll || !ok {
+		return
+	}
+	if listFiltered {
+		filterFrom = i
+		return
+	}
+	selectRowNear(i)
+}
+
+// noteFilter tracks a filter being applied or cleared, returning the row to
+// go back to when it has just been cleared, or -1
+func noteFilter(query string) int {
+	filtered := strings.TrimSpace(query) != "" || letterFilter != 0
+	back := -1
+	switch {
+	case filtered && !listFiltered:
+		filterFrom = gameList.GetCurrentItem()
+	case !filtered && listFiltered:
+		back, filterFrom = filterFrom, -1
+	}
+	listFiltered = filtered
+	if !rememberScroll {
+		return -1
+	}
+	return back
+}
+
+// selectRowNear highlights row i, or the last row if games have gone since;
+// the list's changed func moves off rows that can't be selected
+func selectRowNear(i int) {
+	if len(ui.shownRows) == 0 || i < 0 {
+		return
+	}
+	if i >= len(ui.shownRows) {
+		i = len(ui.shownRows) - 1
+	}
+	pagedGames.Select(i)
+}
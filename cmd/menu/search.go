@@ -0,0 +1,306 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// searchDebounce is how long typing must pause before the list is filtered
+const searchDebounce = 150 * time.Millisecond
+
+var (
+	// searchTimer is the pending debounced filter, if any
+	searchTimer *time.Timer
+
+	pagedGames *PagedGameList
+)
+
+// newSearchBox creates the filter input docked above the game list
+func newSearchBox() *tview.InputField {
+	box := tview.NewInputField().SetLabel("Search: ")
+	box.SetChangedFunc(func(string) {
+		scheduleFilter()
+	})
+	box.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			box.SetText("")
+		}
+		app.SetFocus(gameList)
+	})
+	return box
+}
+
+// scheduleFilter runs filterGames once typing pauses for searchDebounce. The
+// timer reads the box when it fires, so the final query is always applied.
+func scheduleFilter() {
+	if searchTimer != nil {
+		searchTimer.Stop()
+	}
+	searchTimer = time.AfterFunc(searchDebounce, func() {
+		ui.update(func() {
+			filterGames(searchBox.GetText())
+		})
+	})
+}
+
+// searchKey moves focus to the search box on the search key ('/')
+func searchKey(event *tcell.EventKey) *tcell.EventKey {
+	if keys.Search.Matches(event) {
+		app.SetFocus(searchBox)
+		return nil
+	}
+	return event
+}
+
+// filterGames fills the game list with cached games whose title matches query.
+// Without a query, systems with subfolders are shown as a folder tree.
+func filterGames(query string) {
+	var current *gameRow
+	if i := gameList.GetCurrentItem(); i >= 0 && i < len(ui.shownRows) {
+		r := ui.shownRows[i]
+		current = &r
+	}
+	back := noteFilter(query)
+
+	stopMarquee()
+	if currentSystem == recentSystem {
+		ui.shownGames = filteredByLetter(filterRecent(query))
+	} else {
+		ui.shownGames = matchGames(filteredByLetter(filteredByBucket(ui.gameCache)), query)
+	}
+	ui.shownRows = capRows(buildRows(ui.shownGames, currentSystem, query), query)
+	renderRows(pagedGames, ui.shownRows, current, query)
+	if back >= 0 {
+		selectRowNear(back)
+	}
+	refreshLetterIndex()
+	gameHighlighted()
+}
+
+// buildRows lays out matching games as list rows: a folder tree for an
+// unfiltered real system with subfolders, otherwise a flat list
+func buildRows(games []GameEntry, system, query string) []gameRow {
+	games = groupDiscs(games)
+	if _, real := cfg.findSystem(system); real && strings.TrimSpace(query) == "" && hasFolders(games) {
+		return buildTree(games).rows(system, 0, nil)
+	}
+	rows := groupByBaseTitle(games)
+	if wantSeparators(system, query, len(rows)) {
+		rows = addSeparators(rows)
+	}
+	return rows
+}
+
+// renderRows shows rows in p with a placeholder when there are none,
+// keeping the cursor on current if it is still listed
+func renderRows(p *PagedGameList, rows []gameRow, current *gameRow, query string) {
+	p.SetRows(rows)
+	if len(rows) == 0 {
+		if query != "" {
+			addPlaceholderItem(p.List(), "(no matches)")
+		} else {
+			addPlaceholderItem(p.List(), "(no games found)")
+		}
+		return
+	}
+	if current != nil {
+		if i := rowIndex(rows, *current); i >= 0 {
+			p.Select(i)
+		}
+	}
+	if i := p.List().GetCurrentItem(); isSeparatorRowIn(rows, i) {
+		p.Select(i + 1)
+	}
+}
+
+// rowIndex finds the row showing the same folder or game as target, or -1
+func rowIndex(rows []gameRow, target gameRow) int {
+	for i, r := range rows {
+		if sameRow(r, target) {
+			return i
+		}
+	}
+	return -1
+}
+
+// gameIndex finds the row holding game, directly or as a variant, or -1
+func gameIndex(rows []gameRow, game GameEntry) int {
+	for i, r := range rows {
+		if r.isGame() && (sameGame(r.game, game) || hasVariant(r, game)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// gameAt returns the game on list row i; folder, separator and placeholder
+// rows have none
+func gameAt(i int) (GameEntry, bool) {
+	if i < 0 || i >= len(ui.shownRows) || !ui.shownRows[i].isGame() {
+		return GameEntry{}, false
+	}
+	return ui.shownRows[i].game, true
+}
+
+// highlightedGame returns the game under the game list's cursor
+func highlightedGame() (GameEntry, bool) {
+	return gameAt(gameList.GetCurrentItem())
+}
+
+// selectGame moves the cursor to game, reporting whether it is listed
+func selectGame(game GameEntry) bool {
+	i := findRow(func() int { return gameIndex(ui.shownRows, game) })
+	if i < 0 {
+		return false
+	}
+	pagedGames.Select(i)
+	return true
+}
+
+// hasVariant reports whether game is one of a grouped row's variants
+func hasVariant(row gameRow, game GameEntry) bool {
+	for _, v := range row.variants {
+		if sameGame(v, game) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameRow reports whether two rows show the same folder or game
+func sameRow(a, b gameRow) bool {
+	if a.kind != b.kind {
+		return false
+	}
+	switch a.kind {
+	case ItemSeparator, ItemPlaceholder:
+		return a.label == b.label
+	case ItemMore:
+		return true
+	case ItemHeader:
+		return a.folder.Path == b.folder.Path
+	}
+	if a.variants != nil && b.variants != nil {
+		return variantKey(a.game) == variantKey(b.game)
+	}
+	return sameGame(a.game, b.game)
+}
+
+// matchGames returns the games fuzzily matching query, best first with
+// alphabetical tie-breaks. An empty query returns everything in its current
+// order without scoring.
+func matchGames(games []GameEntry, query string) []GameEntry {
+	if strings.TrimSpace(query) == "" {
+		return append([]GameEntry(nil), games...)
+	}
+
+	type scored struct {
+		game  GameEntry
+		score int
+	}
+	var hits []scored
+	for _, g := range games {
+		if s := fuzzyScore(query, displayTitle(g)); s >= 0 {
+			hits = append(hits, scored{g, s})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return normalizeTitle(displayTitle(hits[i].game)) < normalizeTitle(displayTitle(hits[j].game))
+	})
+
+	matches := make([]GameEntry, len(hits))
+	for i, h := range hits {
+		matches[i] = h.game
+	}
+	return matches
+}
+
+// addRowItem appends a row to the game list: a folder header that folds on
+// Enter, or a launchable game, indented by its depth in the tree
+func addRowItem(list itemList, row gameRow) {
+	indent := rowIndent(row)
+	switch row.kind {
+	case ItemSeparator:
+		addSeparatorItem(list, row)
+		return
+	case ItemMore:
+		addMoreItem(list, row)
+		return
+	case ItemPlaceholder:
+		addPlaceholderItem(list, row.label)
+		return
+	case ItemHeader:
+		node := row.folder
+		marker := "▾"
+		if collapsed[folderKey(currentSystem, node.Path)] {
+			marker = "▸"
+		}
+		list.AddItem(indent+styleItem(ItemHeader, marker+" "+tview.Escape(node.Name)+"/"),
+			indent+fmt.Sprintf("  %d games", node.count()), 0, func() {
+				toggleFolder(node)
+			})
+		return
+	}
+
+	game := row.game
+	icon := iconPrefix(game)
+	if variants := row.variants; variants != nil && !fastMode {
+		list.AddItem(indent+icon+tview.Escape(rowTitle(row)), indent+rowText(row), 0, func() {
+			showVariants(variants)
+		})
+		return
+	}
+	title := tview.Escape(rowLabel(row))
+	if (currentSystem == recentSystem || currentSystem == favoritesSystem || currentSystem == mostPlayedSystem) && !gameAvailable(game) {
+		// Imported from another install and not found here
+		title = "[gray]" + title + "[-]"
+	} else if arcadeMissing(game) != nil {
+		title = "[gray]" + title + " (missing ROMs)[-]"
+	}
+	list.AddItem(indent+icon+title, indent+rowText(row), 0, func() {
+		openGame(game)
+	})
+}
+
+// rowText is the detail line shown under a game row
+func rowText(row gameRow) string {
+	if row.variants != nil {
+		return variantText(row)
+	}
+	return secondaryText(row.game)
+}
+
+// sameGame reports whether two entries refer to the same game
+func sameGame(a, b GameEntry) bool {
+	return a.System == b.System && a.Title == b.Title && a.Path == b.Path
+}
+
+// secondaryText is the detail line shown under a game in the list
+func secondaryText(game GameEntry) string {
+	text := ""
+	if isFavorite(game) {
+		text = favoriteMarker
+	}
+	if isNew(game) {
+		text = strings.TrimSpace(text + " " + newBadge)
+	}
+	if game.System != currentSystem {
+		// Lists that mix systems say where each game comes from
+		text = strings.TrimSpace(text + " " + game.System)
+	}
+	if game.Core != "" && onDevice() && !coreInstalled(game.Core) {
+		text = strings.TrimSpace(text + " [red]core " + tview.Escape(game.Core) + " missing[-]")
+	}
+	if secondaryTemplate != "" {
+		text = strings.TrimSpace(text + " " + renderSecondary(game, secondaryTemplate))
+	}
+	return text
+}
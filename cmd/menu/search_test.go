@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestFuzzySearchBothProviders runs the same fuzzy and accent-folded queries
+// over the filesystem and an indexed catalog, which must list the same games
+func TestFuzzySearchBothProviders(t *testing.T) {
+	// Background scans read provider, so it only changes between them; this
+	// cleanup runs after startTestUI's has stopped them
+	old := provider
+	t.Cleanup(func() { provider = old })
+	startTestUI(t, "NES/Super Mario Bros..nes", "NES/Pokémon.nes", "NES/Zelda.nes")
+
+	queries := map[string]string{"smb": "Super Mario Bros.", "pokemon": "Pokémon"}
+	search := func() map[string][]string {
+		found := map[string][]string{}
+		for query := range queries {
+			tryUpdate(t, func() {
+				filterGames(query)
+				for _, g := range ui.shownGames {
+					found[query] = append(found[query], g.Title)
+				}
+			})
+		}
+		return found
+	}
+
+	ui.wait()
+	provider = FSProvider{}
+	openSystemForTest(t, "NES")
+	fromFS := search()
+
+	p, err := OpenSQLiteProvider(filepath.Join(t.TempDir(), "catalog.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if _, err := p.Index(nil); err != nil {
+		t.Fatal(err)
+	}
+	tryUpdate(t, func() {
+		stopMarquee()
+		watchSystem("")
+	})
+	ui.wait()
+	provider = p
+	romCacheMu.Lock()
+	delete(romCache, "NES")
+	romCacheMu.Unlock()
+	openSystemForTest(t, "NES")
+	fromDB := search()
+
+	for query, want := range queries {
+		if got := fromFS[query]; !reflect.DeepEqual(got, []string{want}) {
+			t.Errorf("%q on the filesystem finds %q, want %q", query, got, want)
+		}
+	}
+	if !reflect.DeepEqual(fromFS, fromDB) {
+		t.Errorf("the catalog finds %q, the filesystem %q", fromDB, fromFS)
+	}
+}
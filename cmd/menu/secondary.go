@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// secondaryTemplate lays out the line under each game, e.g.
+// "{region} · {size}"; empty shows only the markers
+var secondaryTemplate string
+
+// secondaryToken matches a {field} in the template
+var secondaryToken = regexp.MustCompile(`\{(\w+)\}`)
+
+// secondaryFields render each template field for a game; "" when the game
+// has no such value
+var secondaryFields = map[string]func(GameEntry) string{
+	"title":  displayTitle,
+	"system": func(g GameEntry) string { return g.System },
+	"region": func(g GameEntry) string { return detectRegion(g.Title) },
+	"folder": func(g GameEntry) string { return g.Folder },
+	"core":   gameCore,
+	"file": func(g GameEntry) string {
+		if g.Path == "" {
+			return ""
+		}
+		return filepath.Base(g.Path)
+	},
+	"ext": func(g GameEntry) string {
+		return strings.TrimPrefix(strings.ToLower(filepath.Ext(g.Path)), ".")
+	},
+	"modified": func(g GameEntry) string {
+		if g.ModTime.IsZero() {
+			return ""
+		}
+		return g.ModTime.Format("2006-01-02")
+	},
+	"size": func(g GameEntry) string {
+		file := g.Path
+		if archive, _, ok := splitArchivePath(g.Path); ok {
+			file = archive
+		}
+		if file == "" {
+			return ""
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return ""
+		}
+		return formatSize(info.Size())
+	},
+}
+
+// renderSecondary fills tmpl's {field}s from entry. Unknown or empty fields
+// render as nothing, and separators left dangling at either end are dropped.
+func renderSecondary(entry GameEntry, tmpl string) string {
+	text := secondaryToken.ReplaceAllStringFunc(tmpl, func(tok string) string {
+		field, ok := secondaryFields[tok[1:len(tok)-1]]
+		if !ok {
+			return ""
+		}
+		return tview.Escape(field(entry))
+	})
+	return strings.Trim(text, " ·|,")
+}
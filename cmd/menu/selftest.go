@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+)
+
+// selfTestReport prints check results and counts the failures
+type selfTestReport struct {
+	w      io.Writer
+	failed int
+}
+
+// pass, fail, warn and skip print one result line
+func (r *selfTestReport) pass(check, format string, args ...interface{}) {
+	r.line("PASS", check, format, args...)
+}
+
+func (r *selfTestReport) fail(check, format string, args ...interface{}) {
+	r.failed++
+	r.line("FAIL", check, format, args...)
+}
+
+func (r *selfTestReport) warn(check, format string, args ...interface{}) {
+	r.line("WARN", check, format, args...)
+}
+
+func (r *selfTestReport) skip(check, format string, args ...interface{}) {
+	r.line("SKIP", check, format, args...)
+}
+
+func (r *selfTestReport) line(result, check, format string, args ...interface{}) {
+	fmt.Fprintf(r.w, "%s  %-10s %s\n", result, check, fmt.Sprintf(format, args...))
+}
+
+// runSelfTest checks the setup without starting the UI: the systems file,
+// every system's folder and core, the launcher and the folders state is
+// saved to. It prints a report and returns 1 if anything failed.
+func runSelfTest() int {
+	r := &selfTestReport{w: os.Stdout}
+	checkConfig(r)
+	checkSystems(r)
+	checkLauncher(r)
+	checkWritable(r)
+	if r.failed > 0 {
+		fmt.Fprintf(r.w, "\n%d checks failed\n", r.failed)
+		return 1
+	}
+	fmt.Fprintln(r.w, "\nall checks passed")
+	return 0
+}
+
+// checkConfig parses the systems file again, reporting every problem
+func checkConfig(r *selfTestReport) {
+	parsed, err := LoadConfig(configPath)
+	var problems *ConfigErrors
+	switch {
+	case errors.As(err, &problems):
+	case err != nil:
+		r.fail("config", "%v", err)
+		return
+	}
+	if _, statErr := os.Stat(configPath); os.IsNotExist(statErr) {
+		r.pass("config", "%s not found, using the built-in systems", configPath)
+	} else {
+		r.pass("config", "%s read, %d systems", configPath, len(parsed.Systems))
+	}
+	if more, ok := parsed.Validate().(*ConfigErrors); ok {
+		if problems == nil {
+			problems = more
+		} else {
+			problems.Problems = append(problems.Problems, more.Problems...)
+		}
+	}
+	if problems == nil {
+		return
+	}
+	for _, p := range problems.Problems {
+		if p.Fatal {
+			r.fail("config", "%s", p.String(problems.Path))
+		} else {
+			r.warn("config", "%s", p.String(problems.Path))
+		}
+	}
+}
+
+// checkSystems makes sure each system's folder can be listed and, on the
+// MiSTer, that its core is installed
+func checkSystems(r *selfTestReport) {
+	for _, sys := range cfg.Systems {
+		dir := sys.romDir()
+		_, err := os.ReadDir(dir)
+		switch {
+		case err == nil:
+			r.pass("folder", "%s: %s", sys.Name, dir)
+		case os.IsNotExist(err) && len(sys.Games) > 0:
+			r.pass("folder", "%s: no %s, %d games configured", sys.Name, dir, len(sys.Games))
+		default:
+			r.fail("folder", "%s: %v", sys.Name, err)
+		}
+
+		if !onDevice() {
+			continue
+		}
+		if path, err := newestCore(sys.coreName()); err != nil {
+			r.fail("core", "%s: %v", sys.Name, err)
+		} else {
+			r.pass("core", "%s: %s", sys.Name, path)
+		}
+	}
+	if !onDevice() {
+		r.skip("core", "not on a MiSTer, cores aren't checked")
+	}
+}
+
+// checkLauncher makes sure launch commands have somewhere to go
+func checkLauncher(r *selfTestReport) {
+	switch l := launcher.(type) {
+	case *DryRunLauncher:
+		r.skip("launcher", "dry run, nothing is sent")
+	case *RemoteLauncher:
+		ctx, cancel := context.WithTimeout(context.Background(), networkTimeout)
+		defer cancel()
+		if err := l.check(ctx); err != nil {
+			r.fail("launcher", "%v", err)
+		} else {
+			r.pass("launcher", "%s@%s answers and has %s", l.User, l.Addr, cmdDevice)
+		}
+	case *DeviceLauncher:
+		if !onDevice() {
+			r.skip("launcher", "not on a MiSTer, %s isn't checked", l.Path)
+			return
+		}
+		if err := checkFIFO(l.Path); err != nil {
+			r.fail("launcher", "%v", err)
+		} else {
+			r.pass("launcher", "%s is being read", l.Path)
+		}
+	}
+}
+
+// checkFIFO makes sure path is a FIFO something is reading, without writing
+// to it
+func checkFIFO(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return fmt.Errorf("%s is not a FIFO", path)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if errors.Is(err, syscall.ENXIO) {
+		return fmt.Errorf("%s: nothing is reading it, is MiSTer running?", path)
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// checkWritable makes sure a file can be created in every folder the
+// systems file and state files are saved to
+func checkWritable(r *selfTestReport) {
+	dirs := map[string]bool{}
+	for _, p := range []string{configPath, favoritesPath(), recentPath(), playCountsPath(),
+		regionsPath(), discsPath(), historyPath(), statePath()} {
+		dirs[absPath(filepath.Dir(p))] = true
+	}
+	var sorted []string
+	for d := range dirs {
+		sorted = append(sorted, d)
+	}
+	sort.Strings(sorted)
+	for _, d := range sorted {
+		f, err := createTemp(d, ".selftest.*")
+		if err != nil {
+			r.fail("writable", "%v", err)
+			continue
+		}
+		f.Close()
+		os.Remove(f.Name())
+		r.pass("writable", "%s", d)
+	}
+}
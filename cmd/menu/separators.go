@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// separatorMin is how many rows a name-sorted list needs before letter
+// separators are shown; negative turns them off
+var separatorMin = 100
+
+// lastGameRow is the game list row highlighted before the current one
+var lastGameRow int
+
+// wantSeparators reports whether a flat list of n rows gets letter
+// separators: only when it is in name order and long enough to need them
+func wantSeparators(system, query string, n int) bool {
+	if separatorMin < 0 || n <= separatorMin || strings.TrimSpace(query) != "" || keepsOrder(system) {
+		return false
+	}
+	return sortMode == SortNameAsc || sortMode == SortNameDesc
+}
+
+// addSeparators puts a letter row before each run of games starting with
+// the same letter
+func addSeparators(rows []gameRow) []gameRow {
+	out := make([]gameRow, 0, len(rows)+len(indexLetters))
+	var last byte
+	for _, r := range rows {
+		if r.isGame() {
+			if letter := titleLetter(rowTitle(r)); letter != last {
+				out = append(out, gameRow{kind: ItemSeparator, label: string(letter), depth: r.depth})
+				last = letter
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// addSeparatorItem shows a separator row, which does nothing when selected
+func addSeparatorItem(list itemList, row gameRow) {
+	list.AddItem(rowIndent(row)+styleItem(ItemSeparator, "── "+tview.Escape(row.label)+" ──"), "", 0, nil)
+}
+
+// skipRow moves list's selection off row i when skip says to, carrying on
+// the way the selection was moving (judged from *last), or back if that
+// runs off the end
+func skipRow(list *tview.List, i int, last *int, skip func(int) bool) {
+	if !skip(i) {
+		*last = i
+		return
+	}
+	step := 1
+	if i < *last {
+		step = -1
+	}
+	n := list.GetItemCount()
+	for _, dir := range []int{step, -step} {
+		for j := i + dir; j >= 0 && j < n; j += dir {
+			if !skip(j) {
+				list.SetCurrentItem(j)
+				return
+			}
+		}
+	}
+}
+
+// isSeparatorRowIn reports whether rows[i] is a separator
+func isSeparatorRowIn(rows []gameRow, i int) bool {
+	return i >= 0 && i < len(rows) && rows[i].kind == ItemSeparator
+}
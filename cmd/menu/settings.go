@@ -0,0 +1,439 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/rivo/tview"
+	"gopkg.in/yaml.v3"
+)
+
+const settingsItem = "Settings"
+
+// applySettings adopts saved settings, except where a flag overrides them
+func applySettings(s Settings) {
+	if s.Sort != "" {
+		if m, ok := parseSortMode(s.Sort); ok {
+			defaultSort, sortMode = m, m
+		} else {
+			logWarn("settings: unknown sort %q", s.Sort)
+		}
+	}
+	if !flagsSet["show-all"] {
+		showAll = s.ShowAll
+	}
+	if !flagsSet["theme"] {
+		themePreset = s.Theme
+	}
+	if !flagsSet["games-dir"] && s.GamesDir != "" {
+		gamesDir = s.GamesDir
+	}
+	if s.NewDays > 0 {
+		newDays = s.NewDays
+	}
+	if s.MessageSeconds > 0 {
+		messageTimeout = time.Duration(s.MessageSeconds) * time.Second
+	}
+	if !flagsSet["group"] {
+		groupSystems = s.GroupSystems
+	}
+	showPaths = s.ShowPaths
+	showIcons = !s.NoIcons
+	infoFirst = s.InfoFirst
+	autoHideSystems = s.AutoHideSystems
+	showScreenshots = s.Screenshots
+	rememberScroll = s.RememberScroll
+	if s.ScreenshotsDir != "" {
+		screenshotsDir = s.ScreenshotsDir
+	}
+	if s.SeparatorMin != 0 {
+		separatorMin = s.SeparatorMin
+	}
+	if s.Panes != nil && checkPanes(s.Panes) == nil {
+		paneWeights = s.Panes
+	}
+	secondaryTemplate = s.Secondary
+	if s.QueueSeconds > 0 {
+		queue.Step = time.Duration(s.QueueSeconds) * time.Second
+	}
+	if s.HistoryMax > 0 {
+		historyMax = s.HistoryMax
+	}
+	if s.MaxItems >= 0 {
+		maxItems = s.MaxItems
+	}
+	if s.BucketMin >= 0 {
+		bucketMin = s.BucketMin
+	}
+	switch {
+	case s.CoverCache > 0:
+		coverCacheBytes = s.CoverCache << 20
+	case s.CoverCache < 0:
+		coverCacheBytes = 0
+	}
+	switch s.LetterKeys {
+	case "", letterKeysJump:
+		letterKeys = letterKeysJump
+	case letterKeysFilter:
+		letterKeys = letterKeysFilter
+	default:
+		logWarn("settings: unknown letter_keys %q", s.LetterKeys)
+	}
+	switch s.GameView {
+	case "", gameViewList:
+		gridView = false
+	case gameViewGrid:
+		gridView = true
+	default:
+		logWarn("settings: unknown game_view %q", s.GameView)
+	}
+	applyIdle(s)
+}
+
+// applyIdle adopts the idle timeout and action from s
+func applyIdle(s Settings) {
+	switch s.IdleAction {
+	case "", idleDim:
+		idleAction = idleDim
+	case idleRandom:
+		idleAction = idleRandom
+	default:
+		logWarn("settings: unknown idle action %q", s.IdleAction)
+	}
+	setIdleTimeout(time.Duration(s.IdleSeconds) * time.Second)
+}
+
+// currentSettings captures the options in effect for saving
+func currentSettings() Settings {
+	return Settings{
+		Sort:     sortModeNames[defaultSort],
+		ShowAll:  showAll,
+		Theme:    themePreset,
+		GamesDir: gamesDir,
+		NewDays:  newDays,
+
+		MessageSeconds:  int(messageTimeout / time.Second),
+		GroupSystems:    groupSystems,
+		ShowPaths:       showPaths,
+		SeparatorMin:    separatorMin,
+		IdleSeconds:     int(time.Duration(atomic.LoadInt64(&idleTimeout)) / time.Second),
+		IdleAction:      idleAction,
+		Panes:           cfg.Settings.Panes,
+		Secondary:       secondaryTemplate,
+		LetterKeys:      letterKeys,
+		HistoryMax:      cfg.Settings.HistoryMax,
+		NoIcons:         !showIcons,
+		GameView:        gameViewName(),
+		MaxItems:        maxItems,
+		BucketMin:       bucketMin,
+		CoverCache:      cfg.Settings.CoverCache,
+		InfoFirst:       infoFirst,
+		AutoHideSystems: autoHideSystems,
+		QueueSeconds:    cfg.Settings.QueueSeconds,
+		Screenshots:     showScreenshots,
+		ScreenshotsDir:  cfg.Settings.ScreenshotsDir,
+		RememberScroll:  rememberScroll,
+	}
+}
+
+// showSettings opens a form for changing options at runtime. Theme, sort and
+// show-all apply straight away; a new games directory asks before reloading.
+func showSettings(app *tview.Application) {
+	s := currentSettings()
+
+	sortOptions := []string{SortNameAsc.String(), SortNameDesc.String(), SortModified.String()}
+	themes := []string{"default"}
+	for name := range themePresets {
+		themes = append(themes, name)
+	}
+	sort.Strings(themes[1:])
+	themeIndex := 0
+	for i, name := range themes {
+		if name == s.Theme {
+			themeIndex = i
+		}
+	}
+
+	form := tview.NewForm()
+	form.AddDropDown("Default sort", sortOptions, int(defaultSort), func(_ string, i int) {
+		if i >= 0 {
+			s.Sort = sortModeNames[SortMode(i)]
+		}
+	})
+	form.AddCheckbox("Show all systems", s.ShowAll, func(checked bool) {
+		s.ShowAll = checked
+	})
+	form.AddCheckbox("Group systems by category", s.GroupSystems, func(checked bool) {
+		s.GroupSystems = checked
+	})
+	form.AddCheckbox("System icons in game list", !s.NoIcons, func(checked bool) {
+		s.NoIcons = !checked
+	})
+	form.AddCheckbox("Show game info before launching", s.InfoFirst, func(checked bool) {
+		s.InfoFirst = checked
+	})
+	form.AddCheckbox("Hide systems after opening one", s.AutoHideSystems, func(checked bool) {
+		s.AutoHideSystems = checked
+	})
+	form.AddCheckbox("Show screenshots instead of covers", s.Screenshots, func(checked bool) {
+		s.Screenshots = checked
+	})
+	form.AddCheckbox("Remember each system's position", s.RememberScroll, func(checked bool) {
+		s.RememberScroll = checked
+	})
+	form.AddDropDown("Theme", themes, themeIndex, func(name string, i int) {
+		if i == 0 {
+			name = ""
+		}
+		s.Theme = name
+	})
+	form.AddInputField("Games directory", s.GamesDir, 40, nil, func(text string) {
+		s.GamesDir = text
+	})
+	form.AddInputField("New for (days)", strconv.Itoa(s.NewDays), 5, tview.InputFieldInteger, func(text string) {
+		if n, err := strconv.Atoi(text); err == nil && n > 0 {
+			s.NewDays = n
+		}
+	})
+	form.AddInputField("Close messages after (s, 0 = never)", strconv.Itoa(s.MessageSeconds), 5, tview.InputFieldInteger, func(text string) {
+		if n, err := strconv.Atoi(text); err == nil && n >= 0 {
+			s.MessageSeconds = n
+		}
+	})
+
+	form.AddInputField("Start lists with at most (0 = all)", strconv.Itoa(s.MaxItems), 5, tview.InputFieldInteger, func(text string) {
+		if n, err := strconv.Atoi(text); err == nil && n >= 0 {
+			s.MaxItems = n
+		}
+	})
+	form.AddInputField("Split systems with over (games, 0 = never)", strconv.Itoa(s.BucketMin), 6, tview.InputFieldInteger, func(text string) {
+		if n, err := strconv.Atoi(text); err == nil && n >= 0 {
+			s.BucketMin = n
+		}
+	})
+	form.AddInputField("Game line, e.g. {region} · {size}", s.Secondary, 30, nil, func(text string) {
+		s.Secondary = text
+	})
+	letterModes := []string{letterKeysJump, letterKeysFilter}
+	letterMode := 0
+	if s.LetterKeys == letterKeysFilter {
+		letterMode = 1
+	}
+	form.AddDropDown("Letter keys", letterModes, letterMode, func(name string, i int) {
+		if i >= 0 {
+			s.LetterKeys = name
+		}
+	})
+	idleActions := []string{idleDim, idleRandom}
+	idleIndex := 0
+	if s.IdleAction == idleRandom {
+		idleIndex = 1
+	}
+	form.AddInputField("Idle after (s, 0 = never)", strconv.Itoa(s.IdleSeconds), 5, tview.InputFieldInteger, func(text string) {
+		if n, err := strconv.Atoi(text); err == nil && n >= 0 {
+			s.IdleSeconds = n
+		}
+	})
+	form.AddDropDown("When idle", idleActions, idleIndex, func(name string, i int) {
+		if i >= 0 {
+			s.IdleAction = name
+		}
+	})
+
+	closeForm := nav.pop
+	form.AddButton("Save", func() {
+		closeForm()
+		saveAndApplySettings(s)
+	})
+	form.AddButton("Cancel", closeForm)
+	form.AddButton("Export lists", func() {
+		closeForm()
+		exportFromMenu()
+	})
+	form.AddButton("Import lists", func() {
+		closeForm()
+		importFromMenu()
+	})
+	form.AddButton("Find duplicates", func() {
+		closeForm()
+		showDuplicates()
+	})
+	form.AddButton("Verify games", func() {
+		closeForm()
+		showVerify()
+	})
+	form.SetCancelFunc(closeForm)
+	form.SetBorder(true).SetTitle(" Settings ")
+
+	nav.push(form)
+}
+
+// changedSettings is saved with every option that differs between shown, what
+// the form opened with, and edited, what it was left with. Flags for this
+// run show up in shown, so they only reach the file when edited.
+func changedSettings(saved, shown, edited Settings) Settings {
+	out := reflect.ValueOf(&saved).Elem()
+	before, after := reflect.ValueOf(shown), reflect.ValueOf(edited)
+	for i := 0; i < out.NumField(); i++ {
+		if !reflect.DeepEqual(before.Field(i).Interface(), after.Field(i).Interface()) {
+			out.Field(i).Set(after.Field(i))
+		}
+	}
+	return saved
+}
+
+// saveSetting changes one setting in the settings file and keeps the rest as
+// saved, so values given by flags for this run aren't written back
+func saveSetting(change func(s *Settings)) {
+	change(&cfg.Settings)
+	if err := saveSettings(configPath, cfg.Settings); err != nil {
+		reportSaveError("settings", err)
+	}
+}
+
+// saveAndApplySettings puts s into effect and saves the options changed in
+// the form; the rest stay as the file has them
+func saveAndApplySettings(s Settings) {
+	oldDir := gamesDir
+	shown := currentSettings()
+
+	if m, ok := parseSortMode(s.Sort); ok && m != defaultSort {
+		defaultSort = m
+		if sortMode = systemSort(currentSystem); !keepsOrder(currentSystem) {
+			sortGames(ui.gameCache, sortMode)
+			filterGames(searchBox.GetText())
+		}
+	}
+	if s.Theme != themePreset {
+		themePreset = s.Theme
+		theme, err := loadTheme(themePath(), themePreset)
+		if err != nil {
+			logWarn("%v", err)
+		}
+		applyTheme(theme)
+		restyle()
+	}
+	if s.NewDays != newDays || s.Secondary != secondaryTemplate || s.NoIcons != !showIcons || s.MaxItems != maxItems {
+		newDays = s.NewDays
+		secondaryTemplate = s.Secondary
+		showIcons = !s.NoIcons
+		maxItems = s.MaxItems
+		filterGames(searchBox.GetText())
+	}
+	messageTimeout = time.Duration(s.MessageSeconds) * time.Second
+	infoFirst = s.InfoFirst
+	autoHideSystems = s.AutoHideSystems
+	rememberScroll = s.RememberScroll
+	if s.Screenshots != showScreenshots {
+		showScreenshots = s.Screenshots
+		refreshDetails()
+	}
+	if s.LetterKeys != letterKeys {
+		letterKeys = s.LetterKeys
+		if letterFilter != 0 {
+			letterFilter = 0
+			filterGames(searchBox.GetText())
+		}
+	}
+	applyIdle(s)
+	if s.ShowAll != showAll || s.GroupSystems != groupSystems || s.BucketMin != bucketMin {
+		showAll = s.ShowAll
+		groupSystems = s.GroupSystems
+		bucketMin = s.BucketMin
+		rebuildSystemList()
+	}
+	refreshStatusBar()
+
+	cfg.Settings = changedSettings(cfg.Settings, shown, s)
+	if err := saveSettings(configPath, cfg.Settings); err != nil {
+		showError(app, T("error.save_settings"), err)
+		return
+	}
+
+	if s.GamesDir != oldDir {
+		confirmReload(s.GamesDir)
+	}
+}
+
+// confirmReload asks before rescanning everything from a new games directory
+func confirmReload(dir string) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf(T("msg.reload"), dir)).
+		AddButtons([]string{T("button.reload"), T("button.later")}).
+		SetDoneFunc(func(button int, _ string) {
+			nav.pop()
+			if button == 0 {
+				reloadGames(dir)
+			}
+		})
+	nav.push(modal)
+}
+
+// reloadGames switches to a new games directory and forgets all scans
+func reloadGames(dir string) {
+	gamesDir = dir
+	romCacheMu.Lock()
+	romCache = map[string][]GameEntry{}
+	romCacheMu.Unlock()
+
+	buildSystemList(cfg)
+	if currentSystem != "" {
+		loadGames(app, currentSystem)
+	}
+}
+
+// saveSettings stores s under "settings:" in the systems file, leaving the
+// rest of the document (and its comments) as it is. A new file also gets
+// the built-in systems so saving settings never empties the menu.
+func saveSettings(path string, s Settings) error {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	case os.IsNotExist(err):
+		base := defaultConfig()
+		if err := doc.Encode(base); err != nil {
+			return err
+		}
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&doc}}
+	default:
+		return err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: top level is not a mapping", path)
+	}
+
+	var value yaml.Node
+	if err := value.Encode(s); err != nil {
+		return err
+	}
+	replaced := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "settings" {
+			root.Content[i+1] = &value
+			replaced = true
+		}
+	}
+	if !replaced {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: "settings"}
+		root.Content = append(root.Content, key, &value)
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, out)
+}
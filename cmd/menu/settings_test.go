@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// TestTogglesKeepFlagValuesOut checks toggling a saved option writes that
+// option alone, not the values flags gave this run
+func TestTogglesKeepFlagValuesOut(t *testing.T) {
+	// Registered first, so it runs once startTestUI's cleanup has stopped
+	// the background scans that read these
+	oldTheme, oldFlags := themePreset, flagsSet
+	t.Cleanup(func() {
+		themePreset, flagsSet = oldTheme, oldFlags
+		showPaths, gridView = false, false
+	})
+	startTestUI(t, "NES/Zelda.nes")
+	if err := os.WriteFile(configPath, []byte("settings:\n  theme: dark\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tryUpdate(t, func() {
+		cfg.Settings = Settings{Theme: "dark"}
+		themePreset, flagsSet = "flagged", map[string]bool{"theme": true}
+		pathsKey(tcell.NewEventKey(tcell.KeyRune, 'P', tcell.ModNone))
+	})
+
+	saved, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Settings.Theme != "dark" {
+		t.Errorf("saved theme = %q, want the file's dark", saved.Settings.Theme)
+	}
+	if !saved.Settings.ShowPaths {
+		t.Error("the paths toggle wasn't saved")
+	}
+}
+
+// TestSettingsFormKeepsFlagValuesOut checks the settings form saves the
+// options changed in it, leaving flagged ones as the file has them
+func TestSettingsFormKeepsFlagValuesOut(t *testing.T) {
+	oldTheme, oldFlags, oldDays := themePreset, flagsSet, newDays
+	t.Cleanup(func() { themePreset, flagsSet, newDays = oldTheme, oldFlags, oldDays })
+	startTestUI(t, "NES/Zelda.nes")
+	if err := os.WriteFile(configPath, []byte("settings:\n  theme: dark\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tryUpdate(t, func() {
+		cfg.Settings = Settings{Theme: "dark"}
+		themePreset, flagsSet = "flagged", map[string]bool{"theme": true}
+		s := currentSettings()
+		s.NewDays = newDays + 1
+		saveAndApplySettings(s)
+	})
+
+	saved, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Settings.Theme != "dark" {
+		t.Errorf("saved theme = %q, want the file's dark", saved.Settings.Theme)
+	}
+	if saved.Settings.NewDays != oldDays+1 {
+		t.Errorf("saved new_days = %d, want %d", saved.Settings.NewDays, oldDays+1)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// sidebarSteps is how many frames the system pane takes to slide shut, and
+// sidebarFrame how long each lasts
+const (
+	sidebarSteps = 4
+	sidebarFrame = 30 * time.Millisecond
+)
+
+var (
+	// autoHideSystems hides the system pane once a system is opened, from
+	// settings.auto_hide_systems
+	autoHideSystems bool
+
+	// systemsHidden is set while the system pane is tucked away;
+	// sidebarLeft counts down the frames of the slide that hid it
+	systemsHidden bool
+	sidebarLeft   int
+)
+
+// systemsWeight scales w, the system pane's share of the width, for the
+// slide in progress: full when shown, shrinking frame by frame once hidden
+func systemsWeight(w int) int {
+	if !systemsHidden {
+		return w * sidebarSteps
+	}
+	return w * sidebarLeft
+}
+
+// hideSystems slides the system pane shut and moves focus to the games.
+// The narrow layout shows one pane anyway, so there it only moves focus.
+func hideSystems() {
+	if systemsHidden {
+		focusPane(gamePane)
+		return
+	}
+	systemsHidden = true
+	sidebarLeft = sidebarSteps
+	focusPane(gamePane)
+	if layoutNarrow {
+		sidebarLeft = 0
+		return
+	}
+	slideSystems()
+}
+
+// slideSystems lays out the next frame of the slide and schedules the one
+// after, until the pane is gone or has been brought back
+func slideSystems() {
+	if !systemsHidden || sidebarLeft == 0 {
+		return
+	}
+	sidebarLeft--
+	relayout()
+	time.AfterFunc(sidebarFrame, func() { ui.update(slideSystems) })
+}
+
+// showSystems brings the system pane back and focuses it
+func showSystems() {
+	if systemsHidden {
+		systemsHidden = false
+		sidebarLeft = 0
+		relayout()
+	}
+	focusPane(systemPane)
+}
+
+// systemsHint labels the systems key for what it will do
+func systemsHint() string {
+	if systemsHidden {
+		return T("hint.show_systems")
+	}
+	return T("hint.hide_systems")
+}
+
+// systemsKey hides or brings back the system pane on the systems key ('S')
+func systemsKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Systems.Matches(event) || !listFocused() {
+		return event
+	}
+	if systemsHidden {
+		showSystems()
+	} else {
+		hideSystems()
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// SortMode is the order games are listed in
+type SortMode int
+
+const (
+	SortNameAsc SortMode = iota
+	SortNameDesc
+	SortModified
+	sortModeCount
+)
+
+// sortMode is the order currently applied to the game list
+var sortMode = SortNameAsc
+
+// defaultSort is the order lists start in for systems without their own
+// sort; it is the one settings save
+var defaultSort = SortNameAsc
+
+// pickedSorts are the orders chosen with the sort key, by system, for the
+// rest of the session
+var pickedSorts = map[string]SortMode{}
+
+// systemSort is the order a system's list opens in: the one last picked for
+// it with the sort key, else its "sort" setting when that is valid, otherwise
+// defaultSort
+func systemSort(system string) SortMode {
+	if m, ok := pickedSorts[system]; ok {
+		return m
+	}
+	if sys, ok := cfg.findSystem(system); ok && sys.Sort != "" {
+		if m, ok := parseSortMode(sys.Sort); ok {
+			return m
+		}
+	}
+	return defaultSort
+}
+
+// String names the sort mode for the status bar
+func (m SortMode) String() string {
+	switch m {
+	case SortNameDesc:
+		return "Z-A"
+	case SortModified:
+		return "newest"
+	}
+	return "A-Z"
+}
+
+// sortModeNames are the names used for sort modes in settings
+var sortModeNames = map[SortMode]string{
+	SortNameAsc:  "name",
+	SortNameDesc: "name-desc",
+	SortModified: "modified",
+}
+
+// parseSortMode looks up a sort mode by its settings name
+func parseSortMode(name string) (SortMode, bool) {
+	for m, n := range sortModeNames {
+		if n == name {
+			return m, true
+		}
+	}
+	return SortNameAsc, false
+}
+
+// sortGames orders entries in place
+func sortGames(entries []GameEntry, mode SortMode) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch mode {
+		case SortNameDesc:
+			return normalizeTitle(displayTitle(a)) > normalizeTitle(displayTitle(b))
+		case SortModified:
+			if !a.ModTime.Equal(b.ModTime) {
+				return a.ModTime.After(b.ModTime)
+			}
+		}
+		return normalizeTitle(displayTitle(a)) < normalizeTitle(displayTitle(b))
+	})
+}
+
+// sortKey cycles through the sort modes on the sort key ('s')
+func sortKey(event *tcell.EventKey) *tcell.EventKey {
+	if !keys.Sort.Matches(event) {
+		return event
+	}
+	sortMode = (sortMode + 1) % sortModeCount
+	pickedSorts[currentSystem] = sortMode
+	if !keepsOrder(currentSystem) {
+		sortGames(ui.gameCache, sortMode)
+		filterGames(searchBox.GetText())
+	}
+	refreshStatusBar()
+	return nil
+}
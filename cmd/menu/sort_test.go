@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// TestSortPickedPerSystem checks a sort picked with the sort key comes back
+// when its system is reopened, without carrying over to other systems
+func TestSortPickedPerSystem(t *testing.T) {
+	startTestUI(t, "NES/Zelda.nes", "SNES/Zelda.sfc")
+	t.Cleanup(func() { pickedSorts = map[string]SortMode{} })
+
+	openSystemForTest(t, "NES")
+	tryUpdate(t, func() { sortKey(tcell.NewEventKey(tcell.KeyRune, 's', tcell.ModNone)) })
+	openSystemForTest(t, "SNES")
+	tryUpdate(t, func() {
+		if sortMode != defaultSort {
+			t.Errorf("SNES opens sorted %s, want %s", sortMode, defaultSort)
+		}
+	})
+	openSystemForTest(t, "NES")
+	tryUpdate(t, func() {
+		if sortMode != SortNameDesc {
+			t.Errorf("NES reopens sorted %s, want %s", sortMode, SortNameDesc)
+		}
+	})
+}
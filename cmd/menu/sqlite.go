@@ -0,0 +1,268 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// indexSchema is the catalog database: one row per game, keyed by system and
+// path (or command for configured games). Titles are fuzzy-matched in memory
+// once loaded, so the full-text table older builds made is dropped.
+const indexSchema = `
+CREATE TABLE IF NOT EXISTS games (
+	id      INTEGER PRIMARY KEY,
+	system  TEXT NOT NULL,
+	key     TEXT NOT NULL,
+	title   TEXT NOT NULL,
+	name    TEXT NOT NULL,
+	command TEXT NOT NULL DEFAULT '',
+	path    TEXT NOT NULL DEFAULT '',
+	core    TEXT NOT NULL DEFAULT '',
+	args    TEXT NOT NULL DEFAULT '',
+	folder  TEXT NOT NULL DEFAULT '',
+	modtime INTEGER NOT NULL DEFAULT 0,
+	seen    INTEGER NOT NULL,
+	UNIQUE (system, key)
+);
+DROP TRIGGER IF EXISTS games_ai;
+DROP TRIGGER IF EXISTS games_ad;
+DROP TRIGGER IF EXISTS games_au;
+DROP TABLE IF EXISTS games_fts;
+`
+
+// indexPath is the catalog database used unless -db names another
+func indexPath() string {
+	return filepath.Join(configDir(), "catalog.db")
+}
+
+// SQLiteProvider is the GameProvider backed by a catalog database built with
+// -build-index. Systems without indexed games, such as recent, favorites and
+// playlists, still come from the filesystem.
+type SQLiteProvider struct {
+	db *sql.DB
+	fs FSProvider
+}
+
+// OpenSQLiteProvider opens (creating if needed) the catalog database at path
+func OpenSQLiteProvider(path string) (*SQLiteProvider, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// One connection keeps writes serialised
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(indexSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &SQLiteProvider{db: db}, nil
+}
+
+// Close closes the database
+func (p *SQLiteProvider) Close() error {
+	return p.db.Close()
+}
+
+// Systems returns the configured systems that have indexed games
+func (p *SQLiteProvider) Systems() []SystemConfig {
+	rows, err := p.db.Query(`SELECT DISTINCT system FROM games`)
+	if err != nil {
+		logWarn("catalog: %v", err)
+		return nil
+	}
+	defer rows.Close()
+	indexed := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if rows.Scan(&name) == nil {
+			indexed[name] = true
+		}
+	}
+	var systems []SystemConfig
+	for _, sys := range cfg.Systems {
+		if indexed[sys.Name] {
+			systems = append(systems, sys)
+		}
+	}
+	return systems
+}
+
+// Games returns a configured system's indexed games in title order; other
+// systems are listed from the filesystem
+func (p *SQLiteProvider) Games(system string) ([]GameEntry, error) {
+	if _, ok := cfg.findSystem(system); !ok {
+		return p.fs.Games(system)
+	}
+	return p.query(`SELECT `+gameColumns+` FROM games WHERE system = ? ORDER BY name`, system)
+}
+
+// Cached reports whether a system is listed without scanning: configured
+// systems always are, since they come from the database
+func (p *SQLiteProvider) Cached(system string) bool {
+	if _, ok := cfg.findSystem(system); ok {
+		return true
+	}
+	return p.fs.Cached(system)
+}
+
+// Unavailable says why a system has nothing to list; the indexed games still
+// need their folder to launch, so this is the filesystem's answer
+func (p *SQLiteProvider) Unavailable(sys SystemConfig) string {
+	return p.fs.Unavailable(sys)
+}
+
+// gameColumns are the columns query reads, in order
+const gameColumns = `system, title, command, path, core, args, folder, modtime`
+
+// query runs a games query and decodes its rows
+func (p *SQLiteProvider) query(q string, args ...interface{}) ([]GameEntry, error) {
+	rows, err := p.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var games []GameEntry
+	for rows.Next() {
+		var g GameEntry
+		var argList string
+		var modTime int64
+		if err := rows.Scan(&g.System, &g.Title, &g.Command, &g.Path, &g.Core, &argList, &g.Folder, &modTime); err != nil {
+			return nil, err
+		}
+		if argList != "" {
+			json.Unmarshal([]byte(argList), &g.Args)
+		}
+		if modTime != 0 {
+			g.ModTime = time.Unix(0, modTime)
+		}
+		games = append(games, g)
+	}
+	return games, rows.Err()
+}
+
+// gameKey identifies a game within its system across index runs
+func gameKey(g GameEntry) string {
+	if g.Path != "" {
+		return g.Path
+	}
+	return "command:" + g.Command + "\x00" + g.Title
+}
+
+// IndexStats counts what an index run changed
+type IndexStats struct {
+	Games, Pruned int
+}
+
+// Index scans every configured system from the filesystem and stores its
+// games, dropping rows for files that are gone and for systems no longer in
+// the config. A system that fails to scan keeps its previous rows.
+func (p *SQLiteProvider) Index(progress func(system string, games int)) (IndexStats, error) {
+	var stats IndexStats
+	run := time.Now().UnixNano()
+	configured := map[string]bool{}
+	for _, sys := range cfg.Systems {
+		configured[sys.Name] = true
+		games, err := systemGames(sys.Name)
+		if err != nil && len(games) == 0 {
+			logWarn("indexing %s: %v", sys.Name, err)
+			continue
+		}
+		pruned, err := p.indexSystem(sys.Name, games, run)
+		if err != nil {
+			return stats, fmt.Errorf("indexing %s: %w", sys.Name, err)
+		}
+		stats.Games += len(games)
+		stats.Pruned += pruned
+		if progress != nil {
+			progress(sys.Name, len(games))
+		}
+	}
+
+	rows, err := p.db.Query(`SELECT DISTINCT system FROM games`)
+	if err != nil {
+		return stats, err
+	}
+	var gone []string
+	for rows.Next() {
+		var name string
+		if rows.Scan(&name) == nil && !configured[name] {
+			gone = append(gone, name)
+		}
+	}
+	rows.Close()
+	for _, name := range gone {
+		res, err := p.db.Exec(`DELETE FROM games WHERE system = ?`, name)
+		if err != nil {
+			return stats, err
+		}
+		n, _ := res.RowsAffected()
+		stats.Pruned += int(n)
+	}
+	return stats, nil
+}
+
+// indexSystem upserts a system's games in one transaction and deletes those
+// not seen in this run, returning how many were deleted
+func (p *SQLiteProvider) indexSystem(system string, games []GameEntry, run int64) (int, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(`INSERT INTO games (system, key, title, name, command, path, core, args, folder, modtime, seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (system, key) DO UPDATE SET title = excluded.title, name = excluded.name,
+			command = excluded.command, path = excluded.path, core = excluded.core, args = excluded.args,
+			folder = excluded.folder, modtime = excluded.modtime, seen = excluded.seen`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	for _, g := range games {
+		var argList string
+		if len(g.Args) > 0 {
+			b, _ := json.Marshal(g.Args)
+			argList = string(b)
+		}
+		var modTime int64
+		if !g.ModTime.IsZero() {
+			modTime = g.ModTime.UnixNano()
+		}
+		if _, err := stmt.Exec(system, gameKey(g), g.Title, displayTitle(g), g.Command, g.Path, g.Core,
+			argList, g.Folder, modTime, run); err != nil {
+			return 0, err
+		}
+	}
+	res, err := tx.Exec(`DELETE FROM games WHERE system = ? AND seen != ?`, system, run)
+	if err != nil {
+		return 0, err
+	}
+	pruned, _ := res.RowsAffected()
+	return int(pruned), tx.Commit()
+}
+
+// runBuildIndex builds or updates the catalog database at path without
+// starting the UI and returns the process exit code
+func runBuildIndex(path string) int {
+	p, err := OpenSQLiteProvider(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "-build-index: %v\n", err)
+		return 1
+	}
+	defer p.Close()
+	stats, err := p.Index(func(system string, games int) {
+		fmt.Printf("%s: %d games\n", system, games)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "-build-index: %v\n", err)
+		return 1
+	}
+	fmt.Printf("indexed %d games into %s, pruned %d\n", stats.Games, path, stats.Pruned)
+	return 0
+}
@@ -0,0 +1,45 @@
+package main
+
+import "path/filepath"
+
+// savedState is the selection remembered between runs
+type savedState struct {
+	System string     `json:"system"`
+	Game   *GameEntry `json:"game,omitempty"`
+}
+
+// statePath is the file the selection is saved to
+func statePath() string {
+	return filepath.Join(configDir(), "state.json")
+}
+
+// saveSelection writes the open system and highlighted game to state.json
+func saveSelection() {
+	st := savedState{System: currentSystem}
+	if st.System == "" {
+		st.System = systemAt(systemList.GetCurrentItem())
+	}
+	if g, ok := highlightedGame(); ok {
+		st.Game = &g
+	}
+	if err := saveJSON(statePath(), st); err != nil {
+		logWarn("saving selection: %v", err)
+	}
+}
+
+// restoreSelection reopens the system and game saved by the last run; a game
+// that no longer exists leaves the list at the top
+func restoreSelection() {
+	var st savedState
+	loadJSON(statePath(), &st)
+	i := systemRow(st.System)
+	if i < 0 || st.System == settingsItem {
+		return
+	}
+	systemList.SetCurrentItem(i)
+	loadGamesThen(st.System, func() {
+		if st.Game != nil {
+			selectGame(*st.Game)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// systemHints lists the keys for the system list, following the key map
+func systemHints() string {
+	return hints("Enter", T("hint.open"), "Tab", T("hint.games"), keys.Search, T("hint.search"), keys.SearchAll, T("hint.search_all"),
+		keys.Refresh, T("hint.refresh"), keys.Random, T("hint.random"), keys.History, T("hint.history"), keys.Systems, T("hint.hide_systems"),
+		keys.Help, T("hint.help"), keys.Quit, T("hint.quit"))
+}
+
+// gameHints lists the keys for the game list, following the key map
+func gameHints() string {
+	launch := T("hint.launch")
+	if fastMode {
+		launch = T("hint.launch_exit")
+	}
+	return hints(keys.Launch, launch, "Tab", T("hint.systems"), keys.Favorite, T("hint.favorite"), keys.Preview, T("hint.preview"),
+		keys.Paths, T("hint.paths"), keys.Copy, T("hint.copy"), keys.Queue, T("hint.queue"), keys.Export, T("hint.export"), keys.View, T("hint.view"), keys.Search, T("hint.search"), keys.SearchAll, T("hint.search_all"),
+		keys.Sort, T("hint.sort"), keys.Systems, systemsHint(), keys.Refresh, T("hint.refresh"), keys.Random, T("hint.random"), keys.History, T("hint.history"),
+		keys.Help, T("hint.help"), keys.Quit, T("hint.quit")) + recentHints()
+}
+
+// recentHints adds the recent list's own keys while it is shown
+func recentHints() string {
+	if currentSystem != recentSystem {
+		return ""
+	}
+	return "  " + hints(keys.Remove, T("hint.remove"), keys.Clear, T("hint.clear"))
+}
+
+// hints formats key/label pairs as "key: label  key: label"
+func hints(pairs ...interface{}) string {
+	var parts []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, tview.Escape(fmt.Sprint(pairs[i]))+": "+fmt.Sprint(pairs[i+1]))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// statusBar is the hint line docked at the bottom of the screen
+var statusBar *tview.TextView
+
+// newStatusBar creates the footer
+func newStatusBar() *tview.TextView {
+	return tview.NewTextView().SetDynamicColors(true)
+}
+
+// updateStatusBar replaces the footer text
+func updateStatusBar(text string) {
+	if statusBar != nil {
+		statusBar.SetText(text)
+	}
+}
+
+// statusNote is a short confirmation shown at the start of the footer until
+// statusNoteTimer clears it
+var (
+	statusNote      string
+	statusNoteTimer *time.Timer
+)
+
+// flashStatus shows note in the footer for a couple of seconds
+func flashStatus(note string) {
+	if statusNoteTimer != nil {
+		statusNoteTimer.Stop()
+	}
+	statusNote = note
+	refreshStatusBar()
+	statusNoteTimer = time.AfterFunc(2*time.Second, func() {
+		ui.update(func() {
+			if statusNote == note {
+				statusNote = ""
+				refreshStatusBar()
+			}
+		})
+	})
+}
+
+// statusNoteText is the footer prefix for a flashed note
+func statusNoteText() string {
+	if statusNote == "" {
+		return ""
+	}
+	return "[green]" + tview.Escape(statusNote) + "[-]  "
+}
+
+// refreshStatusBar shows hints for the focused list and the highlighted game
+func refreshStatusBar() {
+	if activePane != gamePane {
+		updateStatusBar(statusNoteText() + nowPlayingText() + systemHints())
+		return
+	}
+	text := statusNoteText() + nowPlayingText() + gameHints() + "  [yellow]" + fmt.Sprintf(T("status.sort"), sortMode) + "[-]" + letterFilterText()
+	if game, ok := highlightedGame(); ok {
+		where := game.Path
+		if where == "" {
+			where = game.Command
+		}
+		if where != "" {
+			text += "  [gray]" + tview.Escape(where)
+		}
+	}
+	updateStatusBar(text)
+}
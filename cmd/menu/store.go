@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// loadJSON decodes a state file into v; a missing file leaves v untouched
+func loadJSON(path string, v interface{}) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logWarn("%v", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		logWarn("%s: %v", path, err)
+	}
+}
+
+// saveJSON writes v to a state file as indented JSON
+func saveJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, data)
+}
+
+// createTemp makes the temporary file writeAtomic fills; a variable so a
+// failing disk can be simulated
+var createTemp = os.CreateTemp
+
+// writeAtomic replaces path with data by writing a temporary file beside it
+// and renaming it into place, so a crash mid-write leaves the old file
+// whole. An existing file keeps its permissions; a new one gets 0644.
+func writeAtomic(path string, data []byte) error {
+	mode := fs.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	f, err := createTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, mode)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// saveErrorShown records which files have already had a failed save shown,
+// so a read-only disk doesn't stack a modal per launch
+var saveErrorShown = map[string]bool{}
+
+// reportSaveError logs a failed save of what. A permission error is also
+// shown in a modal, once per what, since the change is otherwise lost
+// without notice.
+func reportSaveError(what string, err error) {
+	logWarn("saving %s: %v", what, err)
+	if app == nil || !errors.Is(err, fs.ErrPermission) || saveErrorShown[what] {
+		return
+	}
+	saveErrorShown[what] = true
+	showError(app, fmt.Sprintf(T("error.save"), what), err)
+}
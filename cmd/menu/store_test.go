@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withCreateTemp swaps the temporary file writeAtomic uses for the test
+func withCreateTemp(t *testing.T, f func(dir, pattern string) (*os.File, error)) {
+	t.Helper()
+	old := createTemp
+	createTemp = f
+	t.Cleanup(func() { createTemp = old })
+}
+
+// checkOnly fails unless path holds want and no temporary file was left
+// beside it
+func checkOnly(t *testing.T, path, want string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("%s = %q, want %q", filepath.Base(path), got, want)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("folder holds %v, want only %s", names, filepath.Base(path))
+	}
+}
+
+func TestSaveJSONCreateFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "favorites.json")
+	const old = `["old"]`
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	errDisk := errors.New("disk full")
+	withCreateTemp(t, func(string, string) (*os.File, error) {
+		return nil, errDisk
+	})
+
+	if err := saveJSON(path, []string{"new"}); !errors.Is(err, errDisk) {
+		t.Fatalf("saveJSON error = %v, want %v", err, errDisk)
+	}
+	checkOnly(t, path, old)
+}
+
+func TestSaveJSONWriteFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "favorites.json")
+	const old = `["old"]`
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A temporary file opened read-only fails the write partway through
+	// the save, as a failing card would
+	withCreateTemp(t, func(dir, pattern string) (*os.File, error) {
+		f, err := os.CreateTemp(dir, pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.Close()
+		return os.Open(f.Name())
+	})
+
+	if err := saveJSON(path, []string{"new"}); err == nil {
+		t.Fatal("saveJSON succeeded with a failing write")
+	}
+	checkOnly(t, path, old)
+}
+
+func TestSaveJSONReplaces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "favorites.json")
+	if err := os.WriteFile(path, []byte(`["old"]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveJSON(path, []string{"new"}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode after save = %v, want 0600 kept", info.Mode().Perm())
+	}
+	checkOnly(t, path, "[\n  \"new\"\n]")
+}
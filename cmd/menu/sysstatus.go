@@ -0,0 +1,61 @@
+package main
+
+import "github.com/rivo/tview"
+
+// Status is how ready a system is to play, shown as a glyph beside its name
+type Status int
+
+const (
+	// StatusUnknown is a system not scanned yet
+	StatusUnknown Status = iota
+	// StatusReady has its core installed and games found
+	StatusReady
+	// StatusEmpty has its core installed but no games
+	StatusEmpty
+	// StatusNoCore is missing its core
+	StatusNoCore
+)
+
+// statusGlyphs are drawn per status, coloured unless the terminal is
+// monochrome; the shapes differ so they read without colour too
+var statusGlyphs = map[Status]struct{ glyph, color string }{
+	StatusUnknown: {"·", "gray"},
+	StatusReady:   {"●", "green"},
+	StatusEmpty:   {"○", "yellow"},
+	StatusNoCore:  {"✕", "red"},
+}
+
+// systemStatus judges a configured system by its core and its last count
+func systemStatus(system string) Status {
+	if !coreAvailable(system) {
+		return StatusNoCore
+	}
+	n, ok := ui.systemCounts[system]
+	switch {
+	case !ok:
+		return StatusUnknown
+	case n == 0:
+		return StatusEmpty
+	}
+	return StatusReady
+}
+
+// systemLabel is a system's main text, with the status glyph in front of
+// configured systems. Unavailable ones are dimmed and say so.
+func systemLabel(system string) string {
+	if _, ok := cfg.findSystem(system); !ok {
+		return tview.Escape(system)
+	}
+	g := statusGlyphs[systemStatus(system)]
+	if !systemAvailable(system) {
+		g = statusGlyphs[StatusNoCore]
+		if monochrome {
+			return g.glyph + " " + tview.Escape(system) + " (unavailable)"
+		}
+		return "[gray]" + g.glyph + " " + tview.Escape(system) + " (unavailable)[-]"
+	}
+	if monochrome {
+		return g.glyph + " " + tview.Escape(system)
+	}
+	return "[" + g.color + "]" + g.glyph + "[-] " + tview.Escape(system)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+var (
+	// systemOrder holds the system name behind each systemList row; category
+	// headers are ""
+	systemOrder []string
+	// systemDescriptions are the rows' secondary text before any counts
+	systemDescriptions map[string]string
+)
+
+// buildSystemList fills the system list from the config
+func buildSystemList(cfg *Config) {
+	systemList.Clear()
+	systemOrder = systemOrder[:0]
+	systemDescriptions = map[string]string{}
+	bucketRows = map[string]systemBucket{}
+	loadPlaylists()
+	addSystemItem(recentSystem, "Last played games", 0)
+	addSystemItem(favoritesSystem, "Starred games from every system", 0)
+	addSystemItem(mostPlayedSystem, "Your most launched games", 0)
+	addSystemItem(recentlyAddedSystem, "New games from every system", 0)
+	if info, err := os.Stat(genericDir()); err == nil && info.IsDir() {
+		addSystemItem(unknownSystem, "Unrecognised files in "+genericDir(), 0)
+	}
+	if hasArcade() {
+		addSystemItem(arcadeSystem, "Arcade games from "+arcadeDir, 0)
+		ui.spawn(countArcade)
+	}
+	if hasScripts() {
+		addSystemItem(scriptsSystem, "Run scripts from "+scriptsDir, 0)
+	}
+	for _, name := range playlistNames() {
+		addSystemItem(name, "Playlist", 0)
+		if games, err := provider.Games(name); err == nil {
+			setSystemCount(name, len(games))
+		}
+	}
+	var shown []SystemConfig
+	for _, sys := range provider.Systems() {
+		if !showAll && !coreAvailable(sys.Name) {
+			logInfo("hiding %s: core %s not installed", sys.Name, sys.coreName())
+			continue
+		}
+		shown = append(shown, sys)
+	}
+	if groupSystems {
+		buildGroupedSystemList(shown)
+	} else {
+		for _, sys := range shown {
+			addSystemRows(sys)
+		}
+	}
+	systemOrder = append(systemOrder, settingsItem)
+	systemList.AddItem(settingsItem, "Sort, theme, games folder, export and import", 0, func() {
+		showSettings(app)
+	})
+	setSystemCount(recentSystem, len(recentGames))
+	setSystemCount(favoritesSystem, len(favoriteGames))
+	setSystemCount(mostPlayedSystem, mostPlayedCount())
+
+	// Counts already in memory are part of the first frame; only the
+	// systems still to scan are counted in the background
+	var real []string
+	for _, name := range systemOrder {
+		if _, ok := cfg.findSystem(name); !ok || !systemAvailable(name) {
+			continue
+		}
+		if provider.Cached(name) {
+			if games, err := provider.Games(name); err == nil {
+				setSystemCount(name, len(games))
+				continue
+			}
+		}
+		real = append(real, name)
+	}
+	ui.spawn(func() { warmCaches(real) })
+}
+
+// addSystemItem appends a row that opens system's games
+func addSystemItem(system, description string, hotkey rune) {
+	systemOrder = append(systemOrder, system)
+	systemDescriptions[system] = description
+	resolveAvailability(system)
+	systemList.AddItem(systemLabel(system), description, hotkey, func() {
+		openSystem(system)
+	})
+}
+
+// systemAt returns the system shown at row i of the system list
+func systemAt(i int) string {
+	if i < 0 || i >= len(systemOrder) {
+		return ""
+	}
+	return systemOrder[i]
+}
+
+// systemIndex returns the system list row for system, or -1
+func systemIndex(system string) int {
+	for i, name := range systemOrder {
+		if name == system && name != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// setSystemCount shows "(N games)" after a system's description and
+// updates its status glyph
+func setSystemCount(system string, n int) {
+	old := ui.systemCounts[system]
+	ui.systemCounts[system] = n
+	delete(splitSystems, system)
+	refreshSystemLabel(system)
+	recountBuckets(system, old, n)
+}
+
+// refreshSystemLabel redraws a system's row from its status and last count
+func refreshSystemLabel(system string) {
+	i := systemIndex(system)
+	if i < 0 {
+		return
+	}
+	n, counted := ui.systemCounts[system]
+	if !counted {
+		systemList.SetItemText(i, systemLabel(system), systemDescriptions[system])
+		return
+	}
+	text := fmt.Sprintf("(%d games)", n)
+	if n == 1 {
+		text = "(1 game)"
+	}
+	if desc := systemDescriptions[system]; desc != "" {
+		text = desc + " " + text
+	}
+	systemList.SetItemText(i, systemLabel(system), text)
+}
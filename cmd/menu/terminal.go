@@ -0,0 +1,58 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+var (
+	// noMouse and noColor come from -no-mouse and -no-color
+	noMouse bool
+	noColor bool
+
+	// useMouse and monochrome are what the terminal ends up getting
+	useMouse   = true
+	monochrome bool
+)
+
+// probeTerminal looks up what the terminal can do before the menu takes it
+// over: mouse reporting and enough colours for a theme. Anything missing, or
+// turned off by flag, is left out; the keyboard works the same either way.
+func probeTerminal() {
+	useMouse, monochrome = !noMouse, noColor
+	// Creating a screen only reads terminfo; the terminal isn't touched
+	// until Init, which Run does with its own screen
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return // Run reports this
+	}
+	if !screen.HasMouse() {
+		logInfo("terminal has no mouse support")
+		useMouse = false
+	}
+	if screen.Colors() < 8 {
+		logInfo("terminal has %d colours, using monochrome", screen.Colors())
+		monochrome = true
+	}
+}
+
+// applyMonochrome replaces the theme with plain white on black, which a
+// terminal without colours simply shows as its own two; selections show in
+// reverse video
+func applyMonochrome() {
+	st := &tview.Styles
+	for _, c := range []*tcell.Color{
+		&st.PrimitiveBackgroundColor, &st.ContrastBackgroundColor,
+		&st.MoreContrastBackgroundColor, &st.InverseTextColor,
+	} {
+		*c = tcell.ColorBlack
+	}
+	for _, c := range []*tcell.Color{
+		&st.BorderColor, &st.TitleColor, &st.GraphicsColor, &st.PrimaryTextColor,
+		&st.SecondaryTextColor, &st.TertiaryTextColor, &st.ContrastSecondaryTextColor,
+	} {
+		*c = tcell.ColorWhite
+	}
+	selectionColor = tcell.ColorDefault
+	setItemColors("white", "white")
+}
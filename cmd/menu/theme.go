@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"gopkg.in/yaml.v3"
+)
+
+// Theme names the UI colours; empty fields keep the tview default
+type Theme struct {
+	Preset     string `yaml:"preset"`
+	Foreground string `yaml:"foreground"`
+	Background string `yaml:"background"`
+	Border     string `yaml:"border"`
+	Title      string `yaml:"title"`
+	Secondary  string `yaml:"secondary"`
+	Selection  string `yaml:"selection"`
+
+	// Header colours folder and separator rows, Placeholder the text of
+	// an empty list
+	Header      string `yaml:"header"`
+	Placeholder string `yaml:"placeholder"`
+}
+
+// themePresets are the built-in themes selectable with "preset:" or -theme
+var themePresets = map[string]Theme{
+	"dark": {
+		Foreground: "white", Background: "black", Border: "gray",
+		Title: "white", Secondary: "silver", Selection: "steelblue",
+	},
+	"light": {
+		Foreground: "black", Background: "white", Border: "darkgray",
+		Title: "navy", Secondary: "dimgray", Selection: "lightskyblue",
+	},
+	"amber": {
+		Foreground: "#ffb000", Background: "black", Border: "#805800",
+		Title: "#ffcc00", Secondary: "#cc8c00", Selection: "#664400",
+	},
+}
+
+var (
+	// selectionColor is the list highlight colour, or default to keep tview's
+	selectionColor = tcell.ColorDefault
+
+	// defaultStyles are tview's own colours, restored before each theme
+	defaultStyles = tview.Styles
+)
+
+// themePath is the theme file, next to systems.yaml
+func themePath() string {
+	return filepath.Join(configDir(), "theme.yaml")
+}
+
+// loadTheme reads theme.yaml; preset, when set, overrides the file's preset
+func loadTheme(path, preset string) (Theme, error) {
+	var t Theme
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return t, err
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, &t); err != nil {
+			return t, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if preset != "" {
+		t.Preset = preset
+	}
+	return t.resolve()
+}
+
+// resolve fills fields left empty from the theme's preset
+func (t Theme) resolve() (Theme, error) {
+	if t.Preset == "" {
+		return t, nil
+	}
+	base, ok := themePresets[t.Preset]
+	if !ok {
+		return t, fmt.Errorf("unknown theme preset %q", t.Preset)
+	}
+	pick := func(own, def string) string {
+		if own != "" {
+			return own
+		}
+		return def
+	}
+	return Theme{
+		Preset:     t.Preset,
+		Foreground: pick(t.Foreground, base.Foreground),
+		Background: pick(t.Background, base.Background),
+		Border:     pick(t.Border, base.Border),
+		Title:      pick(t.Title, base.Title),
+		Secondary:  pick(t.Secondary, base.Secondary),
+		Selection:  pick(t.Selection, base.Selection),
+
+		Header:      pick(t.Header, base.Header),
+		Placeholder: pick(t.Placeholder, base.Placeholder),
+	}, nil
+}
+
+// applyTheme maps the theme onto tview.Styles; call before creating widgets
+func applyTheme(t Theme) {
+	tview.Styles = defaultStyles
+	selectionColor = tcell.ColorDefault
+
+	set := func(field, name string, dst *tcell.Color) {
+		if name == "" {
+			return
+		}
+		c, ok := parseColor(name)
+		if !ok {
+			logWarn("theme: invalid %s colour %q, keeping default", field, name)
+			return
+		}
+		*dst = c
+	}
+	set("foreground", t.Foreground, &tview.Styles.PrimaryTextColor)
+	set("background", t.Background, &tview.Styles.PrimitiveBackgroundColor)
+	set("border", t.Border, &tview.Styles.BorderColor)
+	set("title", t.Title, &tview.Styles.TitleColor)
+	set("secondary", t.Secondary, &tview.Styles.SecondaryTextColor)
+	set("secondary", t.Secondary, &tview.Styles.TertiaryTextColor)
+	set("selection", t.Selection, &tview.Styles.ContrastBackgroundColor)
+	set("selection", t.Selection, &selectionColor)
+	setItemColors(checkColor("header", t.Header), checkColor("placeholder", t.Placeholder))
+	if monochrome {
+		applyMonochrome()
+	}
+}
+
+// checkColor returns name if it is a valid colour, warning and returning ""
+// (the default) if not
+func checkColor(field, name string) string {
+	if name == "" {
+		return ""
+	}
+	if _, ok := parseColor(name); !ok {
+		logWarn("theme: invalid %s colour %q, keeping default", field, name)
+		return ""
+	}
+	return name
+}
+
+// parseColor accepts tcell colour names and #rrggbb values
+func parseColor(name string) (tcell.Color, bool) {
+	c := tcell.GetColor(name)
+	if c == tcell.ColorDefault && name != "default" {
+		return c, false
+	}
+	return c, true
+}
+
+// styleList applies the theme's selection colour to a list, or reverse video
+// without colours
+func styleList(l *tview.List) *tview.List {
+	if monochrome {
+		l.SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+		return l
+	}
+	if selectionColor != tcell.ColorDefault {
+		l.SetSelectedStyle(tcell.StyleDefault.
+			Foreground(tview.Styles.PrimaryTextColor).
+			Background(selectionColor))
+	}
+	return l
+}
+
+// restyle recolours the existing widgets after the theme changes at runtime;
+// widgets pick up tview.Styles only when created
+func restyle() {
+	st := tview.Styles
+	base := tcell.StyleDefault.Background(st.PrimitiveBackgroundColor)
+	for _, l := range []*tview.List{systemList, gameList} {
+		l.SetMainTextStyle(base.Foreground(st.PrimaryTextColor)).
+			SetSecondaryTextStyle(base.Foreground(st.TertiaryTextColor)).
+			SetShortcutStyle(base.Foreground(st.SecondaryTextColor)).
+			SetSelectedStyle(tcell.StyleDefault.Foreground(st.PrimitiveBackgroundColor).Background(st.PrimaryTextColor))
+		l.SetBackgroundColor(st.PrimitiveBackgroundColor)
+		styleList(l)
+	}
+
+	searchBox.SetLabelColor(st.SecondaryTextColor).
+		SetFieldBackgroundColor(st.ContrastBackgroundColor).
+		SetFieldTextColor(st.PrimaryTextColor).
+		SetBackgroundColor(st.PrimitiveBackgroundColor)
+	for _, tv := range []*tview.TextView{statusBar, detailsPane, breadcrumb, letterIndex} {
+		if tv == nil {
+			continue
+		}
+		tv.SetTextColor(st.PrimaryTextColor).SetBackgroundColor(st.PrimitiveBackgroundColor)
+	}
+	detailsColumn.SetBorderColor(st.BorderColor).
+		SetTitleColor(st.TitleColor).
+		SetBackgroundColor(st.PrimitiveBackgroundColor)
+	if coverBox != nil {
+		coverBox.SetBackgroundColor(st.PrimitiveBackgroundColor)
+	}
+}
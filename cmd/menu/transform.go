@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CommandTransform is a Launcher that has an external command rewrite each
+// launch before Next sends it. The command gets the game as JSON on stdin,
+// with its command and core filled in, and prints the MiSTer command to
+// send on stdout. Failing, or printing anything but one command line,
+// stops the launch.
+type CommandTransform struct {
+	Command string
+	Next    Launcher
+	Timeout time.Duration
+}
+
+// Launch transforms the game's command and hands it to Next
+func (t *CommandTransform) Launch(game GameEntry) error {
+	return t.LaunchContext(context.Background(), game)
+}
+
+// LaunchContext is Launch that gives up when ctx ends
+func (t *CommandTransform) LaunchContext(ctx context.Context, game GameEntry) error {
+	game, err := t.rewrite(ctx, game)
+	if err != nil {
+		return err
+	}
+	if cl, ok := t.Next.(contextLauncher); ok {
+		return cl.LaunchContext(ctx, game)
+	}
+	return t.Next.Launch(game)
+}
+
+// rewrite returns game with the command the transform made for it
+func (t *CommandTransform) rewrite(ctx context.Context, game GameEntry) (GameEntry, error) {
+	cmd, err := t.transform(ctx, game)
+	if err != nil {
+		return game, err
+	}
+	logInfo("transform: %q became %q", launchCommand(game), cmd)
+	game.Command = cmd
+	return game, nil
+}
+
+// transform runs the command for game and checks what it printed
+func (t *CommandTransform) transform(ctx context.Context, game GameEntry) (string, error) {
+	resolved := game
+	resolved.Command = launchCommand(game)
+	resolved.Core = gameCore(game)
+	input, err := json.Marshal(resolved)
+	if err != nil {
+		return "", fmt.Errorf("transform: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+	c := exec.Command("sh", "-c", t.Command)
+	c.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	c.Stdout, c.Stderr = &stdout, &stderr
+	// The command runs in its own process group where there is one, so a
+	// timeout also kills anything it started that still holds stdout open
+	c.SysProcAttr = procAttr()
+	if err := c.Start(); err != nil {
+		return "", fmt.Errorf("transform: %w", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			killGroup(c.Process)
+		case <-done:
+		}
+	}()
+	err = c.Wait()
+	close(done)
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return "", fmt.Errorf("transform timed out after %v", t.Timeout)
+	case context.Canceled:
+		return "", fmt.Errorf("transform: %w", ctx.Err())
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, strings.SplitN(msg, "\n", 2)[0])
+		}
+		return "", fmt.Errorf("transform: %w", err)
+	}
+
+	cmd := strings.TrimSuffix(stdout.String(), "\n")
+	switch {
+	case strings.TrimSpace(cmd) == "":
+		return "", fmt.Errorf("transform printed no command")
+	case strings.ContainsAny(cmd, "\r\n"):
+		return "", fmt.Errorf("transform printed more than one line")
+	case strings.IndexFunc(cmd, unicode.IsControl) >= 0:
+		return "", fmt.Errorf("transform printed control characters: %q", cmd)
+	}
+	return strings.TrimSpace(cmd), nil
+}
+
+// gameLauncher is the launcher games start through: launcher, behind the
+// transform hook when one is set
+func gameLauncher() Launcher {
+	h := cfg.Hooks
+	if h.Transform == "" {
+		return launcher
+	}
+	return &CommandTransform{Command: h.Transform, Next: launcher, Timeout: h.timeout()}
+}
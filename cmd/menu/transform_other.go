@@ -0,0 +1,18 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// procAttr has no process groups to set up here
+func procAttr() *syscall.SysProcAttr {
+	return nil
+}
+
+// killGroup kills p alone, as its children can't be found from here
+func killGroup(p *os.Process) {
+	p.Kill()
+}
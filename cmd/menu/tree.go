@@ -0,0 +1,120 @@
+package main
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// GameNode is a folder in a system's ROM tree
+type GameNode struct {
+	Name     string
+	Path     string // slash-separated, relative to the system folder; "" is the root
+	Children []*GameNode
+	Games    []GameEntry
+}
+
+// gameRow is one line of the game list: a folder header or a game. A game
+// with regional variants lists them all, and game is the preferred one.
+type gameRow struct {
+	kind     ItemKind
+	game     GameEntry
+	folder   *GameNode
+	variants []GameEntry
+	depth    int
+
+	// label is the letter shown by a separator row, which holds no game
+	label string
+	// more is the game count shown by the row that lifts maxItems
+	more int
+}
+
+// isGame reports whether a row holds a game (or its variants), rather than
+// being a folder, separator or "show all" row
+func (r gameRow) isGame() bool {
+	return r.kind == ItemGame
+}
+
+// collapsed remembers folded folders for this run, keyed by folderKey
+var collapsed = map[string]bool{}
+
+// folderKey identifies a folder across systems
+func folderKey(system, folder string) string {
+	return system + "/" + folder
+}
+
+// hasFolders reports whether any game lives below the system's top folder
+func hasFolders(games []GameEntry) bool {
+	for _, g := range games {
+		if g.Folder != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// buildTree groups games by folder, keeping their order within each folder
+// and sorting the folders by name
+func buildTree(games []GameEntry) *GameNode {
+	root := &GameNode{}
+	nodes := map[string]*GameNode{"": root}
+
+	var nodeFor func(p string) *GameNode
+	nodeFor = func(p string) *GameNode {
+		if n, ok := nodes[p]; ok {
+			return n
+		}
+		parent := path.Dir(p)
+		if parent == "." {
+			parent = ""
+		}
+		n := &GameNode{Name: path.Base(p), Path: p}
+		nodes[p] = n
+		up := nodeFor(parent)
+		up.Children = append(up.Children, n)
+		return n
+	}
+	for _, g := range games {
+		n := nodeFor(g.Folder)
+		n.Games = append(n.Games, g)
+	}
+
+	for _, n := range nodes {
+		sort.Slice(n.Children, func(i, j int) bool {
+			return strings.ToLower(n.Children[i].Name) < strings.ToLower(n.Children[j].Name)
+		})
+	}
+	return root
+}
+
+// count returns the number of games in the folder and below it
+func (n *GameNode) count() int {
+	total := len(n.Games)
+	for _, c := range n.Children {
+		total += c.count()
+	}
+	return total
+}
+
+// rows flattens the tree into list rows, folders first, skipping the
+// contents of collapsed folders
+func (n *GameNode) rows(system string, depth int, out []gameRow) []gameRow {
+	for _, c := range n.Children {
+		out = append(out, gameRow{kind: ItemHeader, folder: c, depth: depth})
+		if !collapsed[folderKey(system, c.Path)] {
+			out = c.rows(system, depth+1, out)
+		}
+	}
+	for _, r := range groupByBaseTitle(n.Games) {
+		r.depth = depth
+		out = append(out, r)
+	}
+	return out
+}
+
+// toggleFolder collapses or expands a folder and redraws the list in place
+func toggleFolder(node *GameNode) {
+	key := folderKey(currentSystem, node.Path)
+	collapsed[key] = !collapsed[key]
+	filterGames(searchBox.GetText())
+}
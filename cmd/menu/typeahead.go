@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// typeAheadTimeout is how long typing may pause before the prefix resets
+const typeAheadTimeout = 700 * time.Millisecond
+
+var (
+	// typeAhead is the prefix typed so far and typeAheadAt when it last grew
+	typeAhead   string
+	typeAheadAt time.Time
+)
+
+// typeAheadActive reports whether a prefix is still being typed, in which
+// case letters extend it instead of acting as hotkeys
+func typeAheadActive() bool {
+	return typeAhead != "" && time.Since(typeAheadAt) < typeAheadTimeout
+}
+
+// typeAheadKey jumps to the first game starting with the letters typed in
+// quick succession. Hotkey letters keep their action as the first key, but
+// once a prefix is underway every letter extends it.
+func typeAheadKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune {
+		typeAhead = ""
+		return event
+	}
+	r := event.Rune()
+	if !typeAheadActive() {
+		typeAhead = ""
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return event
+		}
+	}
+
+	prefix := typeAhead + string(r)
+	i := findRow(func() int { return firstRowWithPrefix(prefix) })
+	if i < 0 {
+		if typeAhead == "" {
+			return event
+		}
+		// Keep the cursor where it is on a typo, but still swallow the key
+		typeAheadAt = time.Now()
+		return nil
+	}
+	typeAhead, typeAheadAt = prefix, time.Now()
+	pagedGames.Select(i)
+	return nil
+}
+
+// firstRowWithPrefix returns the first game row whose title starts with
+// prefix, ignoring case and accents, or -1
+func firstRowWithPrefix(prefix string) int {
+	prefix = normalizeTitle(prefix)
+	for i, r := range ui.shownRows {
+		if !r.isGame() {
+			continue
+		}
+		if strings.HasPrefix(normalizeTitle(rowTitle(r)), prefix) {
+			return i
+		}
+	}
+	return -1
+}
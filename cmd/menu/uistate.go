@@ -0,0 +1,79 @@
+package main
+
+import "sync"
+
+// uiState holds the view state behind the widgets and serialises changes
+// to it and to the widgets. Scanners, watchers and pollers never touch
+// these from their own goroutines; they hand a func to update, which tview
+// runs on its event goroutine between draws. Without a running app, as in
+// headless runs, updates run in place instead. Either way they run under
+// mu, so they never interleave.
+type uiState struct {
+	mu sync.Mutex
+
+	// gameCache holds every game of the current system
+	gameCache []GameEntry
+	// shownGames are the games in view, in order; shownRows are the list
+	// rows behind gameList, which may add folder headers
+	shownGames []GameEntry
+	shownRows  []gameRow
+	// systemCounts are the game counts last shown for each system
+	systemCounts map[string]int
+
+	// jobs counts the background work started with spawn
+	jobs sync.WaitGroup
+	// drawn is closed once the app draws its first frame, so nothing is
+	// queued before Run; the startup state is set directly instead
+	drawn chan struct{}
+}
+
+// ui is the one uiState; every background change to the view goes through it
+var ui = uiState{systemCounts: map[string]int{}}
+
+// update runs f on the UI goroutine and redraws, or with no app straight
+// away. Before the app's first frame it waits for it, so it must not be
+// called on the way to Run. f must not call update itself. On the UI
+// goroutine a panic in f is caught like one in a handler.
+func (u *uiState) update(f func()) {
+	locked := func() {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		f()
+	}
+	if app != nil {
+		if u.drawn != nil {
+			<-u.drawn
+		}
+		app.QueueUpdateDraw(func() {
+			defer recoverCallback()
+			locked()
+		})
+		return
+	}
+	locked()
+}
+
+// markDrawn records that the app is running; trackScreenSize calls it on
+// every draw
+func (u *uiState) markDrawn() {
+	select {
+	case <-u.drawn:
+	default:
+		close(u.drawn)
+	}
+}
+
+// spawn runs f in the background, counted so wait can tell when all such
+// work has finished
+func (u *uiState) spawn(f func()) {
+	u.jobs.Add(1)
+	go func() {
+		defer u.jobs.Done()
+		f()
+	}()
+}
+
+// wait blocks until everything started with spawn has returned
+func (u *uiState) wait() {
+	u.jobs.Wait()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestScanWhileWatching rescans a system from the scanner and its folder
+// watcher at once while games are copied in, so go test -race catches any
+// view state touched off the UI goroutine
+func TestScanWhileWatching(t *testing.T) {
+	startTestUI(t, "NES/Zelda.nes")
+	openSystemForTest(t, "NES")
+
+	const copies = 20
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < copies; i++ {
+			path := filepath.Join(gamesDir, "NES", fmt.Sprintf("Game %02d.nes", i))
+			if err := os.WriteFile(path, []byte("rom"), 0644); err != nil {
+				t.Error(err)
+				return
+			}
+			rescanWatched(context.Background(), "NES")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < copies; i++ {
+			romCacheMu.Lock()
+			delete(romCache, "NES")
+			romCacheMu.Unlock()
+			loaded := make(chan struct{})
+			ui.update(func() { loadGamesThen("NES", func() { close(loaded) }) })
+			<-loaded
+		}
+	}()
+	wg.Wait()
+
+	// Once the copying is over, one more rescan must list every game
+	rescanWatched(context.Background(), "NES")
+	ui.update(func() {
+		if n := len(ui.shownRows); n != copies+1 {
+			t.Errorf("NES lists %d rows, want %d", n, copies+1)
+		}
+		if n := ui.systemCounts["NES"]; n != copies+1 {
+			t.Errorf("NES count = %d, want %d", n, copies+1)
+		}
+		if n := len(ui.gameCache); n != copies+1 {
+			t.Errorf("NES holds %d games, want %d", n, copies+1)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// titleTag matches a "(USA)" or "[!]"-style tag in a ROM title
+var titleTag = regexp.MustCompile(`\s*(\([^)]*\)|\[[^\]]*\])`)
+
+// regionChoices remembers the variant last launched for each base title,
+// keyed by variantKey and saved in regions.json
+var regionChoices = map[string]string{}
+
+// regionsPath is the file the chosen variants are saved to
+func regionsPath() string {
+	return filepath.Join(configDir(), "regions.json")
+}
+
+// loadRegionChoices reads regions.json, leaving no choices if it is missing
+func loadRegionChoices() {
+	loadJSON(regionsPath(), &regionChoices)
+	if regionChoices == nil {
+		regionChoices = map[string]string{}
+	}
+}
+
+// baseTitle strips region and version tags, so "Game (USA) (Rev 1)" is "Game"
+func baseTitle(title string) string {
+	base := strings.TrimSpace(titleTag.ReplaceAllString(title, ""))
+	if base == "" {
+		return title
+	}
+	return base
+}
+
+// variantTags is what sets a variant apart from its base title
+func variantTags(title string) string {
+	tags := strings.Join(titleTag.FindAllString(title, -1), " ")
+	if tags = strings.Join(strings.Fields(tags), " "); tags == "" {
+		return title
+	}
+	return tags
+}
+
+// variantKey identifies a base title within a system's folder
+func variantKey(g GameEntry) string {
+	return g.System + "/" + g.Folder + "/" + strings.ToLower(baseTitle(g.Title))
+}
+
+// groupByBaseTitle folds the variants of each game into one row, in order of
+// first appearance. Games without variants get a plain row as before.
+func groupByBaseTitle(entries []GameEntry) []gameRow {
+	var rows []gameRow
+	index := map[string]int{}
+	for _, g := range entries {
+		key := variantKey(g)
+		if i, ok := index[key]; ok {
+			rows[i].variants = append(rows[i].variants, g)
+			continue
+		}
+		index[key] = len(rows)
+		rows = append(rows, gameRow{game: g, variants: []GameEntry{g}})
+	}
+	for i := range rows {
+		if len(rows[i].variants) == 1 {
+			rows[i].variants = nil
+			continue
+		}
+		rows[i].game = preferredVariant(rows[i].variants)
+	}
+	return rows
+}
+
+// preferredVariant is the variant chosen last time, or the first one
+func preferredVariant(variants []GameEntry) GameEntry {
+	chosen := regionChoices[variantKey(variants[0])]
+	for _, g := range variants {
+		if g.Title == chosen {
+			return g
+		}
+	}
+	return variants[0]
+}
+
+// showVariants lets the user pick which variant of a game to launch, starting
+// at the one picked last time
+func showVariants(variants []GameEntry) {
+	preferred := preferredVariant(variants)
+	labels := make([]string, len(variants))
+	start := 0
+	for i, g := range variants {
+		labels[i] = variantTags(g.Title)
+		if sameGame(g, preferred) {
+			start = i
+		}
+	}
+	showPicker(baseTitle(variants[0].Title), labels, start, func(i int) {
+		chooseVariant(variants[i])
+	})
+}
+
+// chooseVariant remembers game as its title's preferred variant and opens it
+func chooseVariant(game GameEntry) {
+	regionChoices[variantKey(game)] = game.Title
+	if err := saveJSON(regionsPath(), regionChoices); err != nil {
+		reportSaveError("region choices", err)
+	}
+	filterGames(searchBox.GetText())
+	openGame(game)
+}
+
+// variantText is the detail line of a grouped row
+func variantText(row gameRow) string {
+	text := fmt.Sprintf("%d versions", len(row.variants))
+	if extra := secondaryText(row.game); extra != "" {
+		text = extra + " " + text
+	}
+	return text
+}
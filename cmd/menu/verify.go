@@ -0,0 +1,264 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Kinds of problem verifySystem reports
+const (
+	issueMissing    = "missing"
+	issueUnreadable = "unreadable"
+	issueMisfiled   = "misfiled"
+)
+
+// Issue is one problem with a listed game
+type Issue struct {
+	Game   GameEntry
+	Kind   string
+	Detail string
+}
+
+// verifySystem checks every listed game of system with a file: that it
+// still exists, can be read and has an extension the system loads
+func verifySystem(system string) []Issue {
+	issues, _ := verifySystemContext(context.Background(), system, nil)
+	return issues
+}
+
+// verifySystemContext is verifySystem that stops when ctx ends and reports
+// each game checked to progress, if set
+func verifySystemContext(ctx context.Context, system string, progress func()) ([]Issue, error) {
+	games, err := scanGames(ctx, system, nil)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil && len(games) == 0 {
+		return nil, err
+	}
+	var issues []Issue
+	archives := archiveMembers{}
+	for _, g := range games {
+		if err := ctx.Err(); err != nil {
+			return issues, err
+		}
+		if progress != nil {
+			progress()
+		}
+		if g.Path == "" {
+			continue
+		}
+		issue, bad, err := verifyGame(ctx, g, archives)
+		if err != nil {
+			return issues, err
+		}
+		if bad {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// archiveListing is the member names of an archive, or why it can't be read
+type archiveListing struct {
+	names map[string]bool
+	err   error
+}
+
+// archiveMembers holds the archives read during one verify run, so one
+// holding many games is opened once
+type archiveMembers map[string]archiveListing
+
+// list reads file's member names, or returns them from an earlier call. It
+// stops with ctx's error if ctx ends while a large archive is read.
+func (a archiveMembers) list(ctx context.Context, file string) (archiveListing, error) {
+	if l, ok := a[file]; ok {
+		return l, nil
+	}
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		a[file] = archiveListing{err: err}
+		return a[file], nil
+	}
+	defer r.Close()
+	names := make(map[string]bool, len(r.File))
+	for _, zf := range r.File {
+		if err := ctx.Err(); err != nil {
+			return archiveListing{}, err
+		}
+		names[zf.Name] = true
+	}
+	a[file] = archiveListing{names: names}
+	return a[file], nil
+}
+
+// verifyGame checks one game's file, or its archive and member. The error is
+// only ctx's, when it ends partway.
+func verifyGame(ctx context.Context, g GameEntry, archives archiveMembers) (Issue, bool, error) {
+	file, member, inArchive := splitArchivePath(g.Path)
+	ext := filepath.Ext(g.Path)
+	if inArchive {
+		l, err := archives.list(ctx, file)
+		if err != nil {
+			return Issue{}, false, err
+		}
+		if l.err != nil {
+			return openIssue(g, file, l.err), true, nil
+		}
+		if !l.names[member] {
+			return Issue{g, issueMissing, member + " not in " + filepath.Base(file)}, true, nil
+		}
+		ext = path.Ext(member)
+	} else {
+		f, err := os.Open(g.Path)
+		if err != nil {
+			return openIssue(g, g.Path, err), true, nil
+		}
+		f.Close()
+	}
+	if len(extensionsFor(g.System)) == 0 || hasExtension(g.System, ext) {
+		return Issue{}, false, nil
+	}
+	if !inArchive {
+		// Misnamed ROMs the scan recognised by their header are fine
+		if _, claimed := systemForExtension(ext); !claimed && sniffSystem(g.Path) == g.System {
+			return Issue{}, false, nil
+		}
+	}
+	detail := fmt.Sprintf("%s is not a %s extension", ext, g.System)
+	if other, ok := systemForExtension(ext); ok {
+		detail += ", looks like " + other
+	}
+	return Issue{g, issueMisfiled, detail}, true, nil
+}
+
+// openIssue is the problem behind a file that wouldn't open
+func openIssue(g GameEntry, file string, err error) Issue {
+	if os.IsNotExist(err) {
+		return Issue{g, issueMissing, file}
+	}
+	return Issue{g, issueUnreadable, err.Error()}
+}
+
+// verifyReport formats issues for the report view
+func verifyReport(systems []string, issues []Issue) string {
+	if len(issues) == 0 {
+		return fmt.Sprintf("No problems found in %s.", strings.Join(systems, ", "))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d problems found\n", len(issues))
+	system := ""
+	for _, is := range issues {
+		if is.Game.System != system {
+			system = is.Game.System
+			fmt.Fprintf(&b, "\n[yellow]%s[-]\n", tview.Escape(system))
+		}
+		fmt.Fprintf(&b, "  [red]%-10s[-] %s\n             %s\n", is.Kind, tview.Escape(displayTitle(is.Game)), tview.Escape(is.Detail))
+	}
+	return b.String()
+}
+
+// showVerify checks the current system, or every system, behind a progress
+// modal that can cancel the run, then shows what was found
+func showVerify() {
+	if _, ok := cfg.findSystem(currentSystem); !ok {
+		runVerify(systemNames())
+		return
+	}
+	modal := tview.NewModal().
+		SetText("Verify which games?").
+		AddButtons([]string{currentSystem, "All systems", "Cancel"}).
+		SetDoneFunc(func(i int, _ string) {
+			nav.pop()
+			switch i {
+			case 0:
+				runVerify([]string{currentSystem})
+			case 1:
+				runVerify(systemNames())
+			}
+		})
+	nav.push(modal)
+}
+
+// systemNames lists the systems the provider offers
+func systemNames() []string {
+	var names []string
+	for _, sys := range provider.Systems() {
+		names = append(names, sys.Name)
+	}
+	return names
+}
+
+// runVerify verifies systems in the background, showing the running count
+func runVerify(systems []string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var checked int64
+	var finished int32
+
+	modal := tview.NewModal()
+	modal.SetText(T("msg.verifying")).
+		AddButtons([]string{T("button.cancel")}).
+		SetDoneFunc(func(int, string) {
+			if atomic.CompareAndSwapInt32(&finished, 0, 1) {
+				cancel()
+				nav.close(modal)
+			}
+		})
+	nav.push(modal)
+
+	ui.spawn(func() {
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			text := fmt.Sprintf(T("msg.verify_progress"), atomic.LoadInt64(&checked))
+			ui.update(func() {
+				if atomic.LoadInt32(&finished) == 0 {
+					modal.SetText(text)
+				}
+			})
+		}
+	})
+
+	ui.spawn(func() {
+		var issues []Issue
+		for _, system := range systems {
+			found, err := verifySystemContext(ctx, system, func() {
+				atomic.AddInt64(&checked, 1)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				logWarn("verifying %s: %v", system, err)
+			}
+			issues = append(issues, found...)
+		}
+		report := verifyReport(systems, issues)
+		ui.update(func() {
+			if !atomic.CompareAndSwapInt32(&finished, 0, 1) {
+				return // cancelled while the result was queued
+			}
+			cancel()
+			nav.close(modal)
+			view := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetText(report)
+			view.SetDoneFunc(func(tcell.Key) { nav.pop() })
+			view.SetBorder(true).SetTitle(" Verify (Esc to close) ")
+			nav.push(view)
+		})
+	})
+}
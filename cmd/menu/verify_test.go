@@ -0,0 +1,61 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestVerifyReadsArchivesOnce checks every game in an archive is verified
+// from one reading of it, and that a cancelled run stops
+func TestVerifyReadsArchivesOnce(t *testing.T) {
+	old := cfg
+	t.Cleanup(func() { cfg = old })
+	cfg = defaultConfig()
+
+	pack := filepath.Join(t.TempDir(), "Pack.zip")
+	f, err := os.Create(pack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for _, name := range []string{"A.nes", "B.nes"} {
+		if _, err := w.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	archives := archiveMembers{}
+	game := func(member string) GameEntry {
+		return GameEntry{Title: member, System: "NES", Path: pack + "/" + member}
+	}
+	if issue, bad, err := verifyGame(context.Background(), game("A.nes"), archives); err != nil || bad {
+		t.Fatalf("A.nes: %v %v", issue, err)
+	}
+	// Once read, the archive isn't opened again
+	if err := os.Remove(pack); err != nil {
+		t.Fatal(err)
+	}
+	if issue, bad, err := verifyGame(context.Background(), game("B.nes"), archives); err != nil || bad {
+		t.Errorf("B.nes: %v %v", issue, err)
+	}
+	if issue, bad, _ := verifyGame(context.Background(), game("C.nes"), archives); !bad || issue.Kind != issueMissing {
+		t.Errorf("C.nes: %v, want %s", issue, issueMissing)
+	}
+	if issue, bad, _ := verifyGame(context.Background(), game("A.nes"), archiveMembers{}); !bad || issue.Kind != issueMissing {
+		t.Errorf("A.nes in a removed archive: %v, want %s", issue, issueMissing)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := verifySystemContext(ctx, "NES", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled verify returned %v", err)
+	}
+}
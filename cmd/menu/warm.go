@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// scanWorkers is how many systems warmCaches scans at once (-scan-workers)
+var scanWorkers = runtime.NumCPU()
+
+// warmCaches scans systems in the background with a pool of scanWorkers,
+// filling the scan cache and each system's game count as it finishes. A
+// failing system is logged and skipped without stopping the others.
+func warmCaches(systems []string) {
+	// The generic folder feeds every system, so scan it once up front
+	if _, err := genericGames(context.Background(), ""); err != nil {
+		logWarn("%s: %v", genericDir(), err)
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	workers := scanWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for system := range jobs {
+				games, err := provider.Games(system)
+				if err != nil && len(games) == 0 {
+					logWarn("warming %s: %v", system, err)
+					system := system
+					ui.update(func() {
+						setSystemCount(system, 0)
+					})
+					continue
+				}
+				system, n := system, len(games)
+				ui.update(func() {
+					setSystemCount(system, n)
+				})
+			}
+		}()
+	}
+	for _, system := range systems {
+		jobs <- system
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Every scan is cached by now, so these are only walks over memory
+	for _, system := range []string{recentlyAddedSystem, unknownSystem} {
+		games, _ := provider.Games(system)
+		system, n := system, len(games)
+		ui.update(func() {
+			setSystemCount(system, n)
+		})
+	}
+}
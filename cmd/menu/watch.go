@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how quiet a folder must go before the list is refreshed,
+// so a bulk copy rescans once rather than per file
+const watchDebounce = time.Second
+
+// The folder being watched for the open system, and how to stop watching;
+// only touched on the UI goroutine
+var (
+	watchedDir string
+	stopWatch  context.CancelFunc
+)
+
+// watchSystem follows the ROM folder of the system being shown, dropping
+// the previous watch. Virtual systems aren't watched.
+func watchSystem(system string) {
+	dir := ""
+	if sys, ok := cfg.findSystem(system); ok {
+		dir = sys.romDir()
+	}
+	if dir == watchedDir {
+		return
+	}
+	if stopWatch != nil {
+		stopWatch()
+		stopWatch = nil
+	}
+	watchedDir = dir
+	if dir == "" {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopWatch = cancel
+	ui.spawn(func() { watchRoms(ctx, system, dir) })
+}
+
+// watchRoms rescans system after files appear in or vanish from dir (or any
+// folder under it) until ctx ends
+func watchRoms(ctx context.Context, system, dir string) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logWarn("watching %s: %v", dir, err)
+		return
+	}
+	defer watcher.Close()
+	// Watches aren't recursive, so add every folder
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			if err := watcher.Add(path); err != nil {
+				logWarn("watching %s: %v", path, err)
+			}
+		}
+		return nil
+	})
+
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					watcher.Add(event.Name)
+				}
+			}
+			debounce.Reset(watchDebounce)
+		case <-debounce.C:
+			rescanWatched(ctx, system)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logWarn("watching %s: %v", dir, err)
+		}
+	}
+}
+
+// rescanWatched rescans system in the background and swaps the result into
+// the list if it is still showing, keeping the highlighted game when it
+// survived and otherwise staying at the same position
+func rescanWatched(ctx context.Context, system string) {
+	romCacheMu.Lock()
+	delete(romCache, system)
+	romCacheMu.Unlock()
+
+	games, err := scanGames(ctx, system, nil)
+	if ctx.Err() != nil {
+		return
+	}
+	ui.update(func() {
+		if currentSystem != system {
+			return
+		}
+		i := gameList.GetCurrentItem()
+		prev, hadGame := highlightedGame()
+		showGames(system, games, err)
+		if hadGame && selectGame(prev) {
+			return
+		}
+		if n := len(ui.shownRows); n > 0 {
+			if i >= n {
+				i = n - 1
+			}
+			pagedGames.Select(i)
+		}
+	})
+}